@@ -0,0 +1,244 @@
+// Package client talks to a running LodeTime runtime over the CLI protocol
+// (see .lodetime/contracts/cli-protocol.yaml). Requests and responses are
+// single JSON objects terminated by a newline.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultEndpoint is the tcp-loopback transport declared in the protocol contract.
+	DefaultEndpoint = "127.0.0.1:9998"
+	// DefaultTimeout bounds dialing and each request/response round trip.
+	DefaultTimeout = 2 * time.Second
+)
+
+var (
+	// ErrConnect is returned when the runtime cannot be reached.
+	ErrConnect = errors.New("connect")
+	// ErrProtocol is returned when the exchange itself fails (I/O, bad JSON).
+	ErrProtocol = errors.New("protocol")
+	// ErrResponse matches every *Error returned by the runtime.
+	ErrResponse = errors.New("response")
+)
+
+// Error codes sent by the runtime in failed responses.
+const (
+	CodeInvalidJSON    = "invalid_json"
+	CodeNotImplemented = "not_implemented"
+	CodeNotFound       = "not_found"
+)
+
+// Error is a failed response ({"ok": false, "error": {...}}) from the runtime.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrResponse, e.Code, e.Message)
+}
+
+// Is reports ErrResponse as a match so callers can test the error class.
+func (e *Error) Is(target error) bool {
+	return target == ErrResponse
+}
+
+// IsCode reports whether err is a runtime error with the given code.
+func IsCode(err error, code string) bool {
+	var respErr *Error
+	return errors.As(err, &respErr) && respErr.Code == code
+}
+
+// Options configures a Client. The zero value uses DefaultTimeout.
+type Options struct {
+	// Timeout bounds dialing and each request. Zero means DefaultTimeout.
+	Timeout time.Duration
+
+	// The remaining options apply to the stdio transport only.
+
+	// RuntimeCommand is the runtime to spawn. Empty means DefaultRuntimeCommand.
+	RuntimeCommand []string
+	// Dir is the working directory of the spawned runtime (the project root).
+	Dir string
+	// Stderr receives the runtime's stderr. Nil discards it.
+	Stderr io.Writer
+	// StartTimeout is added to the first request's deadline while the runtime
+	// boots. Zero means DefaultStartTimeout.
+	StartTimeout time.Duration
+}
+
+// conn is the byte stream a Client exchanges JSONL over.
+type conn interface {
+	io.ReadWriteCloser
+	SetDeadline(t time.Time) error
+}
+
+// Client is a connection to the runtime. Calls are serialized, so a Client
+// is safe for concurrent use.
+type Client struct {
+	mu      sync.Mutex
+	conn    conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	nextID  uint64
+
+	// startup extends the first request's deadline; it is cleared after use.
+	startup time.Duration
+	// skipNoise ignores stdout lines that are not JSON objects, such as
+	// compiler or console logger output from a spawned runtime.
+	skipNoise bool
+}
+
+// Dial connects to the runtime at endpoint (see ParseEndpoint). For the stdio
+// endpoint it spawns the runtime instead.
+func Dial(ctx context.Context, endpoint string, opts Options) (*Client, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	addr, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if addr.Network == StdioEndpoint {
+		process, err := startProcess(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		startup := opts.StartTimeout
+		if startup <= 0 {
+			startup = DefaultStartTimeout
+		}
+		return &Client{
+			conn:      process,
+			reader:    bufio.NewReader(process),
+			timeout:   timeout,
+			startup:   startup,
+			skipNoise: true,
+		}, nil
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	netConn, err := dialer.DialContext(ctx, addr.Network, addr.Address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConnect, err)
+	}
+
+	return &Client{
+		conn:    netConn,
+		reader:  bufio.NewReader(netConn),
+		timeout: timeout,
+	}, nil
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+type response struct {
+	RequestID string          `json:"request_id,omitempty"`
+	Ok        bool            `json:"ok"`
+	Data      json.RawMessage `json:"data"`
+	Error     *Error          `json:"error"`
+}
+
+// Call sends cmd with args and decodes the response data into out (which may
+// be nil). Protocol commands without a typed wrapper can be issued this way.
+func (c *Client) Call(ctx context.Context, cmd string, args map[string]any, out any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	requestID := strconv.FormatUint(c.nextID, 10)
+
+	request := make(map[string]any, len(args)+2)
+	for key, value := range args {
+		request[key] = value
+	}
+	request["cmd"] = cmd
+	request["request_id"] = requestID
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+
+	// The connection deadline is the client timeout; ctx, including its own
+	// deadline, cancels through AfterFunc so an early stop reports ctx.Err().
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout + c.startup))
+	c.startup = 0
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := c.conn.Write(append(payload, '\n')); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+
+	line, err := c.readLine()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+	// Runtimes that predate request IDs do not echo them back.
+	if resp.RequestID != "" && resp.RequestID != requestID {
+		return fmt.Errorf("%w: response for request %s, expected %s", ErrProtocol, resp.RequestID, requestID)
+	}
+
+	if !resp.Ok {
+		if resp.Error != nil {
+			return resp.Error
+		}
+		return &Error{Code: "unknown", Message: "unknown error"}
+	}
+
+	if out == nil || len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+
+	return nil
+}
+
+func (c *Client) readLine() ([]byte, error) {
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if c.skipNoise && !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+		return line, nil
+	}
+}
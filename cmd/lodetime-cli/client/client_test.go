@@ -0,0 +1,315 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveJSONL answers each request line using respond until the client hangs up.
+func serveJSONL(t *testing.T, respond func(request map[string]any) map[string]any) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	serveListener(t, listener, respond)
+
+	return listener.Addr().String()
+}
+
+func serveListener(t *testing.T, listener net.Listener, respond func(request map[string]any) map[string]any) {
+	t.Helper()
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var request map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+				return
+			}
+			data, _ := json.Marshal(respond(request))
+			_, _ = conn.Write(append(data, '\n'))
+		}
+	}()
+}
+
+func dialTest(t *testing.T, endpoint string) *Client {
+	t.Helper()
+
+	c, err := Dial(context.Background(), endpoint, Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestClientCommandsShareConnection(t *testing.T) {
+	var seen []string
+	endpoint := serveJSONL(t, func(request map[string]any) map[string]any {
+		seen = append(seen, request["cmd"].(string))
+		switch request["cmd"] {
+		case "status":
+			return map[string]any{"ok": true, "request_id": request["request_id"], "data": map[string]any{"mode": "connected"}}
+		case "component":
+			return map[string]any{"ok": true, "data": map[string]any{"id": request["id"], "status": "implemented", "dependents": []string{"cli"}}}
+		case "list":
+			if request["status"] != "planned" {
+				t.Errorf("expected status filter planned, got %v", request["status"])
+			}
+			return map[string]any{"ok": true, "data": map[string]any{"components": []map[string]any{{"id": "file-watcher", "status": "planned"}}}}
+		}
+		return map[string]any{"ok": false, "error": map[string]any{"code": CodeNotImplemented, "message": "command not implemented"}}
+	})
+
+	c := dialTest(t, endpoint)
+	ctx := context.Background()
+
+	status, err := c.Status(ctx, false)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if status["mode"] != "connected" {
+		t.Fatalf("expected mode connected, got %v", status["mode"])
+	}
+
+	component, err := c.Component(ctx, "cli-socket")
+	if err != nil {
+		t.Fatalf("component: %v", err)
+	}
+	if component.ID != "cli-socket" || len(component.Dependents) != 1 {
+		t.Fatalf("unexpected component: %+v", component)
+	}
+
+	components, err := c.List(ctx, "planned")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(components) != 1 || components[0].ID != "file-watcher" {
+		t.Fatalf("unexpected list: %+v", components)
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 requests on one connection, got %v", seen)
+	}
+}
+
+func TestClientReturnsTypedErrors(t *testing.T) {
+	endpoint := serveJSONL(t, func(request map[string]any) map[string]any {
+		return map[string]any{"ok": false, "error": map[string]any{"code": CodeNotImplemented, "message": "command not implemented"}}
+	})
+
+	c := dialTest(t, endpoint)
+	_, err := c.Affected(context.Background(), "graph-server")
+	if !errors.Is(err, ErrResponse) {
+		t.Fatalf("expected ErrResponse, got %v", err)
+	}
+	if !IsCode(err, CodeNotImplemented) {
+		t.Fatalf("expected code %s, got %v", CodeNotImplemented, err)
+	}
+}
+
+func TestClientRejectsMismatchedRequestID(t *testing.T) {
+	endpoint := serveJSONL(t, func(request map[string]any) map[string]any {
+		return map[string]any{"ok": true, "request_id": "stale", "data": map[string]any{}}
+	})
+
+	c := dialTest(t, endpoint)
+	if _, err := c.Status(context.Background(), false); !errors.Is(err, ErrProtocol) {
+		t.Fatalf("expected ErrProtocol, got %v", err)
+	}
+}
+
+func TestDialUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	endpoint := listener.Addr().String()
+	_ = listener.Close()
+
+	if _, err := Dial(context.Background(), endpoint, Options{Timeout: time.Second}); !errors.Is(err, ErrConnect) {
+		t.Fatalf("expected ErrConnect, got %v", err)
+	}
+}
+
+func TestDialUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "lode.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	serveListener(t, listener, func(request map[string]any) map[string]any {
+		return map[string]any{"ok": true, "data": map[string]any{"mode": "connected"}}
+	})
+
+	c := dialTest(t, UnixScheme+socketPath)
+	status, err := c.Status(context.Background(), false)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if status["mode"] != "connected" {
+		t.Fatalf("expected mode connected, got %v", status["mode"])
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	cases := []struct {
+		endpoint string
+		want     Endpoint
+	}{
+		{"127.0.0.1:9998", Endpoint{Network: "tcp", Address: "127.0.0.1:9998"}},
+		{"tcp://localhost:9000", Endpoint{Network: "tcp", Address: "localhost:9000"}},
+		{"unix:///tmp/lode.sock", Endpoint{Network: "unix", Address: "/tmp/lode.sock"}},
+	}
+	for _, tc := range cases {
+		got, err := ParseEndpoint(tc.endpoint)
+		if err != nil {
+			t.Fatalf("ParseEndpoint(%q): %v", tc.endpoint, err)
+		}
+		if got != tc.want {
+			t.Fatalf("ParseEndpoint(%q) = %+v, want %+v", tc.endpoint, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"unix://", "http://localhost:9998"} {
+		if _, err := ParseEndpoint(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestProjectSocketPathFallsBackForLongRoots(t *testing.T) {
+	short := ProjectSocketPath("/work/app/.lodetime")
+	if short != "/work/app/.lodetime/lode.sock" {
+		t.Fatalf("unexpected short path: %s", short)
+	}
+
+	long := ProjectSocketPath("/" + strings.Repeat("deep/", 30) + ".lodetime")
+	if len(long) > maxSocketPath || !strings.HasSuffix(long, ".sock") {
+		t.Fatalf("expected short fallback path, got %s", long)
+	}
+	if long != ProjectSocketPath("/"+strings.Repeat("deep/", 30)+".lodetime") {
+		t.Fatalf("expected stable fallback path")
+	}
+}
+
+// TestStdioHelperRuntime is not a real test: TestDialStdio re-executes the
+// test binary with this test selected to stand in for a runtime process.
+func TestStdioHelperRuntime(t *testing.T) {
+	if os.Getenv("LODE_STDIO_HELPER") != "1" {
+		t.Skip("helper process for TestDialStdio")
+	}
+	if os.Getenv("LODE_RUNTIME_ENDPOINT") != StdioEndpoint {
+		os.Exit(2)
+	}
+
+	fmt.Println("Compiling 3 files (.ex)")
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			os.Exit(3)
+		}
+		data, _ := json.Marshal(map[string]any{
+			"ok":         true,
+			"request_id": request["request_id"],
+			"data":       map[string]any{"mode": "connected", "cmd": request["cmd"]},
+		})
+		fmt.Println(string(data))
+	}
+	os.Exit(0)
+}
+
+func TestDialStdio(t *testing.T) {
+	t.Setenv("LODE_STDIO_HELPER", "1")
+
+	c, err := Dial(context.Background(), StdioEndpoint, Options{
+		Timeout:        time.Second,
+		RuntimeCommand: []string{os.Args[0], "-test.run=^TestStdioHelperRuntime$"},
+		StartTimeout:   5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		status, err := c.Status(context.Background(), false)
+		if err != nil {
+			t.Fatalf("status %d: %v", i, err)
+		}
+		if status["mode"] != "connected" {
+			t.Fatalf("expected mode connected, got %v", status["mode"])
+		}
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestSubscribeStreamsEvents(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		if !scanner.Scan() {
+			return
+		}
+		var request map[string]any
+		_ = json.Unmarshal(scanner.Bytes(), &request)
+		if request["cmd"] != "subscribe" || fmt.Sprint(request["types"]) != "[component_status_changed]" {
+			t.Errorf("unexpected subscribe request: %v", request)
+		}
+		_, _ = fmt.Fprintf(conn, `{"ok":true,"request_id":%q,"data":{"subscribed":["component_status_changed"]}}`+"\n", request["request_id"])
+		_, _ = fmt.Fprintln(conn, `{"event":"component_status_changed","at":"2026-01-02T15:04:05Z","data":{"path":"lib/a.ex"}}`)
+		scanner.Scan() // hold the connection open until the client closes it
+	}()
+
+	c := dialTest(t, listener.Addr().String())
+	sub, err := c.Subscribe(context.Background(), []string{EventComponentStatus})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+
+	event, err := sub.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next error: %v", err)
+	}
+	if event.Type != EventComponentStatus || event.Data["path"] != "lib/a.ex" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := sub.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded while idle, got %v", err)
+	}
+}
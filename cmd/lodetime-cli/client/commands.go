@@ -0,0 +1,205 @@
+package client
+
+import "context"
+
+// Component is a component as reported by the runtime. Live fields (Health,
+// LastTest, Dependents) are only filled in by runtimes that track them.
+type Component struct {
+	ID                  string      `json:"id"`
+	Name                string      `json:"name,omitempty"`
+	Status              string      `json:"status,omitempty"`
+	Description         string      `json:"description,omitempty"`
+	Location            string      `json:"location,omitempty"`
+	Language            string      `json:"language,omitempty"`
+	DependsOn           []string    `json:"depends_on,omitempty"`
+	ImplementsContracts []string    `json:"implements_contracts,omitempty"`
+	Tests               []string    `json:"tests,omitempty"`
+	Health              string      `json:"health,omitempty"`
+	LastTest            *TestResult `json:"last_test,omitempty"`
+	Dependents          []string    `json:"dependents,omitempty"`
+}
+
+// TestResult is the outcome of the most recent test run for a component.
+type TestResult struct {
+	Status string `json:"status"`
+	At     string `json:"at,omitempty"`
+	Passed int    `json:"passed,omitempty"`
+	Failed int    `json:"failed,omitempty"`
+}
+
+// DependencyNode is one component in a dependency tree. Cycle marks a node
+// already present higher up the same branch; its children are not repeated.
+type DependencyNode struct {
+	ID       string           `json:"id"`
+	Cycle    bool             `json:"cycle,omitempty"`
+	Children []DependencyNode `json:"children,omitempty"`
+}
+
+// Dependencies is the response to the dependencies command. With Reverse set
+// the tree follows dependents instead of dependencies.
+type Dependencies struct {
+	ID           string           `json:"id"`
+	Depth        int              `json:"depth"`
+	Reverse      bool             `json:"reverse,omitempty"`
+	Dependencies []DependencyNode `json:"dependencies"`
+}
+
+// Affected is the response to the affected command: every component that
+// may be impacted by a change to ID.
+type Affected struct {
+	ID       string   `json:"id"`
+	Affected []string `json:"affected"`
+}
+
+type componentList struct {
+	Components []Component `json:"components"`
+}
+
+// Status returns the runtime status payload. The payload is open-ended, so
+// it is returned as decoded JSON.
+func (c *Client) Status(ctx context.Context, verbose bool) (map[string]any, error) {
+	payload := map[string]any{}
+	if err := c.Call(ctx, "status", map[string]any{"verbose": verbose}, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// Component returns a single component by ID.
+func (c *Client) Component(ctx context.Context, id string) (*Component, error) {
+	var component Component
+	if err := c.Call(ctx, "component", map[string]any{"id": id}, &component); err != nil {
+		return nil, err
+	}
+	return &component, nil
+}
+
+// Dependencies returns the dependency tree of id up to depth levels
+// (0 means unlimited).
+func (c *Client) Dependencies(ctx context.Context, id string, depth int) (*Dependencies, error) {
+	var deps Dependencies
+	if err := c.Call(ctx, "dependencies", map[string]any{"id": id, "depth": depth}, &deps); err != nil {
+		return nil, err
+	}
+	return &deps, nil
+}
+
+// Dependents returns the reverse dependency tree of id: the components that
+// depend on it, up to depth levels (0 means unlimited).
+func (c *Client) Dependents(ctx context.Context, id string, depth int) (*Dependencies, error) {
+	var deps Dependencies
+	args := map[string]any{"id": id, "depth": depth, "reverse": true}
+	if err := c.Call(ctx, "dependencies", args, &deps); err != nil {
+		return nil, err
+	}
+	return &deps, nil
+}
+
+// Affected returns the components affected by a change to id.
+func (c *Client) Affected(ctx context.Context, id string) (*Affected, error) {
+	var affected Affected
+	if err := c.Call(ctx, "affected", map[string]any{"id": id}, &affected); err != nil {
+		return nil, err
+	}
+	return &affected, nil
+}
+
+// List returns all components, optionally filtered by status ("" for all).
+func (c *Client) List(ctx context.Context, status string) ([]Component, error) {
+	args := map[string]any{}
+	if status != "" {
+		args["status"] = status
+	}
+
+	var list componentList
+	if err := c.Call(ctx, "list", args, &list); err != nil {
+		return nil, err
+	}
+	return list.Components, nil
+}
+
+// Finding is one validation result.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+type findingList struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Validate asks the runtime to validate the project it has loaded. paths,
+// when given, scopes validation to the components owning them.
+func (c *Client) Validate(ctx context.Context, paths []string) ([]Finding, error) {
+	args := map[string]any{}
+	if len(paths) > 0 {
+		args["paths"] = paths
+	}
+
+	var list findingList
+	if err := c.Call(ctx, "validate", args, &list); err != nil {
+		return nil, err
+	}
+	return list.Findings, nil
+}
+
+// Change is a file changed during a work session and the component whose
+// location contains it ("" when none does).
+type Change struct {
+	Path      string `json:"path"`
+	Component string `json:"component,omitempty"`
+}
+
+// WorkReport is returned by begin-work, checkpoint and end-work.
+type WorkReport struct {
+	Phase       string    `json:"phase"`
+	Label       string    `json:"label,omitempty"`
+	StartedAt   string    `json:"started_at,omitempty"`
+	Base        string    `json:"base,omitempty"`
+	At          string    `json:"at"`
+	Status      string    `json:"status"`
+	Checkpoints int       `json:"checkpoints,omitempty"`
+	Changes     []Change  `json:"changes,omitempty"`
+	Affected    []string  `json:"affected,omitempty"`
+	Findings    []Finding `json:"findings,omitempty"`
+}
+
+// Work phases, in session order.
+const (
+	PhaseBeginWork  = "begin-work"
+	PhaseCheckpoint = "checkpoint"
+	PhaseEndWork    = "end-work"
+)
+
+// BeginWork starts a work session: the runtime observes changes without
+// reporting until the next checkpoint.
+func (c *Client) BeginWork(ctx context.Context, label string) (*WorkReport, error) {
+	return c.work(ctx, PhaseBeginWork, label)
+}
+
+// Checkpoint asks the runtime to validate the current snapshot now.
+func (c *Client) Checkpoint(ctx context.Context, label string) (*WorkReport, error) {
+	return c.work(ctx, PhaseCheckpoint, label)
+}
+
+// EndWork runs full validation and reports everything that changed during
+// the session.
+func (c *Client) EndWork(ctx context.Context, label string) (*WorkReport, error) {
+	return c.work(ctx, PhaseEndWork, label)
+}
+
+func (c *Client) work(ctx context.Context, phase, label string) (*WorkReport, error) {
+	args := map[string]any{}
+	if label != "" {
+		args["label"] = label
+	}
+
+	var report WorkReport
+	if err := c.Call(ctx, phase, args, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// UnixScheme prefixes Unix domain socket endpoints (unix:///path/lode.sock).
+	UnixScheme = "unix://"
+	// TCPScheme optionally prefixes TCP endpoints (tcp://host:port).
+	TCPScheme = "tcp://"
+
+	projectSocketName = "lode.sock"
+	// Conservative sun_path limit shared by Linux (108) and macOS (104).
+	maxSocketPath = 103
+)
+
+// Endpoint is a parsed runtime address suitable for net.Dial.
+type Endpoint struct {
+	Network string
+	Address string
+}
+
+func (e Endpoint) String() string {
+	switch e.Network {
+	case "unix":
+		return UnixScheme + e.Address
+	case StdioEndpoint:
+		return StdioEndpoint
+	}
+	return e.Address
+}
+
+// ParseEndpoint accepts host:port, tcp://host:port, unix:///path and stdio.
+func ParseEndpoint(endpoint string) (Endpoint, error) {
+	switch {
+	case endpoint == StdioEndpoint:
+		return Endpoint{Network: StdioEndpoint}, nil
+
+	case strings.HasPrefix(endpoint, UnixScheme):
+		path := strings.TrimPrefix(endpoint, UnixScheme)
+		if path == "" {
+			return Endpoint{}, fmt.Errorf("unix endpoint %q has no socket path", endpoint)
+		}
+		return Endpoint{Network: "unix", Address: path}, nil
+
+	case strings.HasPrefix(endpoint, TCPScheme):
+		return Endpoint{Network: "tcp", Address: strings.TrimPrefix(endpoint, TCPScheme)}, nil
+
+	case strings.Contains(endpoint, "://"):
+		return Endpoint{}, fmt.Errorf("unsupported endpoint scheme in %q", endpoint)
+	}
+
+	return Endpoint{Network: "tcp", Address: endpoint}, nil
+}
+
+// ProjectSocketPath returns the per-project socket path for the project whose
+// .lodetime/ directory is lodeDir. The socket lives inside lodeDir unless that
+// path would exceed the platform limit, in which case a stable per-project name
+// under the temp directory is used.
+func ProjectSocketPath(lodeDir string) string {
+	path := filepath.Join(lodeDir, projectSocketName)
+	if len(path) <= maxSocketPath {
+		return path
+	}
+
+	sum := sha256.Sum256([]byte(lodeDir))
+	name := fmt.Sprintf("lode-%d-%s.sock", os.Getuid(), hex.EncodeToString(sum[:6]))
+	return filepath.Join(os.TempDir(), name)
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event types pushed to subscribers. Checkpoint events are planned for when
+// the runtime handles the checkpoint command.
+const (
+	EventFileChanged     = "file_changed"
+	EventComponentStatus = "component_status_changed"
+	EventErrorLogged     = "error_logged"
+)
+
+// EventTypes lists every event type in the protocol.
+var EventTypes = []string{
+	EventFileChanged,
+	EventComponentStatus,
+	EventErrorLogged,
+}
+
+// Event is one line pushed by the runtime after a subscribe, e.g.
+// {"event":"error_logged","at":"...","data":{"process":"graph-server"}}.
+type Event struct {
+	Type string         `json:"event"`
+	At   string         `json:"at,omitempty"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// Subscription reads events from a connection that has been switched to
+// streaming. The client must not be used for other calls afterwards.
+type Subscription struct {
+	c *Client
+}
+
+// Subscribe asks the runtime to stream events of the given types (all types
+// when empty) on this connection.
+func (c *Client) Subscribe(ctx context.Context, types []string) (*Subscription, error) {
+	args := map[string]any{}
+	if len(types) > 0 {
+		args["types"] = types
+	}
+	if err := c.Call(ctx, "subscribe", args, nil); err != nil {
+		return nil, err
+	}
+	return &Subscription{c: c}, nil
+}
+
+// Next blocks until the next event arrives, ctx is done or the connection
+// closes. Only ctx bounds the wait; the client timeout does not apply.
+func (s *Subscription) Next(ctx context.Context) (Event, error) {
+	c := s.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Cancellation, including ctx's own deadline, goes through AfterFunc so
+	// a read that stops early always sees ctx.Err().
+	_ = c.conn.SetDeadline(time.Time{})
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	line, err := c.readLine()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Event{}, ctxErr
+		}
+		return Event{}, fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+
+	var event Event
+	if err := json.Unmarshal(line, &event); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+	if event.Type == "" {
+		return Event{}, fmt.Errorf("%w: expected event, got %s", ErrProtocol, line)
+	}
+	return event, nil
+}
+
+// Close ends the subscription by closing the connection.
+func (s *Subscription) Close() error {
+	return s.c.Close()
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// StdioEndpoint selects the stdio transport: the client spawns the runtime
+// and exchanges JSONL over its stdin/stdout.
+const StdioEndpoint = "stdio"
+
+const (
+	// DefaultStartTimeout allows for compiling and booting a spawned runtime
+	// before its first response.
+	DefaultStartTimeout = 60 * time.Second
+
+	stopTimeout = 2 * time.Second
+)
+
+// DefaultRuntimeCommand starts the runtime from the project root.
+var DefaultRuntimeCommand = []string{"mix", "run", "--no-halt"}
+
+// processConn is a runtime child process viewed as a stream: writes go to its
+// stdin and reads come from its stdout.
+type processConn struct {
+	cmd    *exec.Cmd
+	stdin  *os.File
+	stdout *os.File
+	done   chan error
+}
+
+func startProcess(ctx context.Context, opts Options) (*processConn, error) {
+	command := opts.RuntimeCommand
+	if len(command) == 0 {
+		command = DefaultRuntimeCommand
+	}
+
+	// os.Pipe ends support deadlines, which exec's StdinPipe/StdoutPipe do not.
+	stdinRead, stdinWrite, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConnect, err)
+	}
+	stdoutRead, stdoutWrite, err := os.Pipe()
+	if err != nil {
+		stdinRead.Close()
+		stdinWrite.Close()
+		return nil, fmt.Errorf("%w: %v", ErrConnect, err)
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = opts.Dir
+	cmd.Env = append(os.Environ(), "LODE_RUNTIME_ENDPOINT="+StdioEndpoint)
+	cmd.Stdin = stdinRead
+	cmd.Stdout = stdoutWrite
+	cmd.Stderr = opts.Stderr
+
+	if err := cmd.Start(); err != nil {
+		stdinRead.Close()
+		stdinWrite.Close()
+		stdoutRead.Close()
+		stdoutWrite.Close()
+		return nil, fmt.Errorf("%w: start %s: %v", ErrConnect, command[0], err)
+	}
+	// The child holds its own copies of these ends.
+	stdinRead.Close()
+	stdoutWrite.Close()
+
+	conn := &processConn{
+		cmd:    cmd,
+		stdin:  stdinWrite,
+		stdout: stdoutRead,
+		done:   make(chan error, 1),
+	}
+	go func() {
+		conn.done <- cmd.Wait()
+	}()
+
+	if err := ctx.Err(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrConnect, err)
+	}
+
+	return conn, nil
+}
+
+func (p *processConn) Read(b []byte) (int, error) {
+	return p.stdout.Read(b)
+}
+
+func (p *processConn) Write(b []byte) (int, error) {
+	return p.stdin.Write(b)
+}
+
+func (p *processConn) SetDeadline(t time.Time) error {
+	return errors.Join(p.stdin.SetDeadline(t), p.stdout.SetDeadline(t))
+}
+
+// Close closes the runtime's stdin, which tells it to shut down, and kills it
+// if it has not exited within stopTimeout.
+func (p *processConn) Close() error {
+	_ = p.stdin.Close()
+	defer p.stdout.Close()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-time.After(stopTimeout):
+		_ = p.cmd.Process.Kill()
+		<-p.done
+		return nil
+	}
+}
+
+var _ conn = (*processConn)(nil)
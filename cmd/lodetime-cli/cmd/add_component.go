@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/lodetime/lodetime-cli/terminal"
+	"github.com/lodetime/lodetime-cli/validate"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// componentIDPattern matches kebab-case component IDs such as graph-server.
+var componentIDPattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+var (
+	addComponentSpec       componentSpec
+	addComponentBuildOrder bool
+	addComponentYes        bool
+)
+
+var addComponentCmd = &cobra.Command{
+	Use:   "add-component <id>",
+	Short: "Scaffold .lodetime/components/<id>.yaml",
+	Long: `Writes a new component spec with schema_version, checking that the ID is
+free and that every depends_on component and contract exists.
+
+Fields not given as flags are prompted for when stdin is a terminal; --yes
+takes the defaults instead. --build-order appends the component to build_order
+in config.yaml; every depends_on component must already be listed there.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+
+		spec := addComponentSpec
+		spec.ID = args[0]
+		if spec.Name == "" {
+			spec.Name = defaultComponentName(spec.ID)
+		}
+		addToBuildOrder := addComponentBuildOrder
+
+		if !addComponentYes && terminal.IsTerminal(int(os.Stdin.Fd())) {
+			asked := func(flag string) bool { return !cmd.Flags().Changed(flag) }
+			prompt := newPrompter(os.Stdin, os.Stdout)
+			if err := prompt.componentSpec(&spec, asked); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			if asked("build-order") && project.Config.Has("build_order") {
+				addToBuildOrder, err = prompt.confirm("Add to build_order?", true)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			}
+		}
+
+		if err := checkComponentSpec(project, spec); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		rel := filepath.ToSlash(filepath.Join("components", spec.ID+".yaml"))
+		path := filepath.Join(lodeDir, filepath.FromSlash(rel))
+		if _, err := os.Stat(path); err == nil {
+			fmt.Fprintf(os.Stderr, "%s already exists\n", filepath.Join(".lodetime", rel))
+			os.Exit(1)
+		}
+
+		configPath := filepath.Join(lodeDir, "config.yaml")
+		var config []byte
+		if addToBuildOrder {
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to read config.yaml:", err)
+				os.Exit(1)
+			}
+			config, err = insertBuildOrder(data, spec.ID, spec.DependsOn)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to update build_order:", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create components/:", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, []byte(renderComponentYAML(spec)), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to write component:", err)
+			os.Exit(1)
+		}
+		// The spec is removed again if config.yaml cannot be written, so a
+		// failed update leaves nothing behind.
+		if config != nil {
+			if err := os.WriteFile(configPath, config, 0644); err != nil {
+				os.Remove(path)
+				fmt.Fprintln(os.Stderr, "Failed to write config.yaml:", err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("Created %s\n", filepath.Join(".lodetime", rel))
+		if addToBuildOrder {
+			fmt.Printf("Added %s to build_order\n", spec.ID)
+		}
+
+		// Surface what validation thinks of the new spec, e.g. a planned
+		// component whose location already holds code.
+		for _, finding := range validate.Dir(lodeDir).Findings {
+			if finding.File == rel {
+				fmt.Printf("  %s [%s] %s\n", strings.ToUpper(string(finding.Severity)), finding.Rule, finding.Message)
+			}
+		}
+	},
+}
+
+func init() {
+	flags := addComponentCmd.Flags()
+	flags.StringVar(&addComponentSpec.Name, "name", "", "display name (default: the ID in title case)")
+	flags.StringVar(&addComponentSpec.Status, "status", model.StatusPlanned, "status: "+strings.Join(model.Statuses, ", "))
+	flags.StringVar(&addComponentSpec.Location, "location", "", "path of the component's code, e.g. lib/app/graph/")
+	flags.StringVar(&addComponentSpec.Language, "language", "", "implementation language, e.g. elixir or go")
+	flags.StringVar(&addComponentSpec.Description, "description", "", "one-line description")
+	flags.StringSliceVar(&addComponentSpec.DependsOn, "depends-on", nil, "component IDs this one depends on")
+	flags.StringSliceVar(&addComponentSpec.Contracts, "contracts", nil, "contract IDs this one implements")
+	flags.BoolVar(&addComponentBuildOrder, "build-order", false, "append the component to build_order in config.yaml (its dependencies must be listed)")
+	flags.BoolVarP(&addComponentYes, "yes", "y", false, "do not prompt; use flags and defaults")
+}
+
+// componentSpec holds the fields add-component writes.
+type componentSpec struct {
+	ID          string
+	Name        string
+	Status      string
+	Location    string
+	Language    string
+	Description string
+	DependsOn   []string
+	Contracts   []string
+}
+
+// defaultComponentName turns graph-server into "Graph Server".
+func defaultComponentName(id string) string {
+	words := strings.Split(id, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// checkComponentSpec rejects specs that would not validate: bad or taken IDs,
+// unknown statuses, and references to components or contracts that do not
+// exist.
+func checkComponentSpec(project *model.Project, spec componentSpec) error {
+	if !componentIDPattern.MatchString(spec.ID) {
+		return fmt.Errorf("invalid component id %q (expected kebab-case, e.g. graph-server)", spec.ID)
+	}
+	if _, ok := project.Component(spec.ID); ok {
+		return fmt.Errorf("component %s already exists", spec.ID)
+	}
+	if spec.Name == "" {
+		return errors.New("name is required")
+	}
+	if !containsString(model.Statuses, spec.Status) {
+		return fmt.Errorf("invalid status %q (expected one of: %s)", spec.Status, strings.Join(model.Statuses, ", "))
+	}
+	if spec.Location == "" {
+		return errors.New("location is required (--location)")
+	}
+
+	unknown := []string{}
+	for _, dep := range spec.DependsOn {
+		if _, ok := project.Component(dep); !ok || dep == spec.ID {
+			unknown = append(unknown, dep)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown depends_on component(s): %s", strings.Join(unknown, ", "))
+	}
+	for _, contract := range spec.Contracts {
+		if _, ok := project.Contract(contract); !ok {
+			unknown = append(unknown, contract)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown contract(s): %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// renderComponentYAML writes spec in the key order of the existing component
+// files, with lists in flow style.
+func renderComponentYAML(spec componentSpec) string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "id: %s\n", spec.ID)
+	fmt.Fprintf(builder, "schema_version: %d\n", validate.SchemaVersion)
+	fmt.Fprintf(builder, "name: %s\n", yamlScalar(spec.Name))
+	fmt.Fprintf(builder, "status: %s\n", spec.Status)
+	if spec.Language != "" {
+		fmt.Fprintf(builder, "language: %s\n", yamlScalar(spec.Language))
+	}
+	if spec.Description != "" {
+		fmt.Fprintf(builder, "description: %s\n", yamlScalar(spec.Description))
+	}
+	fmt.Fprintf(builder, "location: %s\n", yamlScalar(spec.Location))
+	fmt.Fprintf(builder, "depends_on: [%s]\n", strings.Join(spec.DependsOn, ", "))
+	if len(spec.Contracts) > 0 {
+		fmt.Fprintf(builder, "implements_contracts: [%s]\n", strings.Join(spec.Contracts, ", "))
+	}
+	return builder.String()
+}
+
+// yamlScalar quotes value only when YAML needs it.
+func yamlScalar(value string) string {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%q", value)
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// insertBuildOrder appends id to build_order, editing the text so comments and
+// formatting elsewhere in config.yaml are kept. Every dependency must already
+// be listed, so appending keeps them ahead of the new component.
+func insertBuildOrder(config []byte, id string, dependsOn []string) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(config, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("config.yaml is not a mapping")
+	}
+
+	var key, value *yaml.Node
+	mapping := document.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == "build_order" {
+			key, value = mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	if key == nil {
+		return nil, errors.New("config.yaml has no build_order")
+	}
+	if value.Kind != yaml.SequenceNode {
+		return nil, errors.New("build_order is not a list")
+	}
+	listed := map[string]bool{}
+	for _, item := range value.Content {
+		listed[item.Value] = true
+	}
+	if listed[id] {
+		return nil, fmt.Errorf("build_order already lists %s", id)
+	}
+	for _, dependency := range dependsOn {
+		if !listed[dependency] {
+			return nil, fmt.Errorf("build_order does not list dependency %s; add it first", dependency)
+		}
+	}
+
+	lines := strings.SplitAfter(string(config), "\n")
+	if value.Style&yaml.FlowStyle != 0 {
+		// A flow list is edited in place when it fits on its line.
+		at := value.Line - 1
+		line := lines[at]
+		end := strings.LastIndex(line, "]")
+		if value.Line != key.Line || end < 0 {
+			return nil, errors.New("build_order spans several lines; add the component by hand")
+		}
+		entry := id
+		if len(value.Content) > 0 {
+			entry = ", " + id
+		}
+		lines[at] = line[:end] + entry + line[end:]
+		return []byte(strings.Join(lines, "")), nil
+	}
+
+	last := value.Content[len(value.Content)-1]
+	indent := strings.Repeat(" ", last.Column-3)
+	entry := indent + "- " + id + "\n"
+	at := last.Line
+	if at > 0 && !strings.HasSuffix(lines[at-1], "\n") {
+		lines[at-1] += "\n"
+	}
+	updated := append(lines[:at:at], append([]string{entry}, lines[at:]...)...)
+	return []byte(strings.Join(updated, "")), nil
+}
+
+// prompter asks for values on an interactive terminal.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask shows label with def and returns the answer, or def for an empty one.
+func (p *prompter) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+	answer, err := p.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// askList reads a comma- or space-separated list.
+func (p *prompter) askList(label string, def []string) ([]string, error) {
+	answer, err := p.ask(label+" (comma-separated)", strings.Join(def, ", "))
+	if err != nil {
+		return nil, err
+	}
+	return strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }), nil
+}
+
+func (p *prompter) confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer, err := p.ask(label+" ("+hint+")", "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// componentSpec prompts for every field asked reports as not given by flag.
+func (p *prompter) componentSpec(spec *componentSpec, asked func(flag string) bool) error {
+	fields := []struct {
+		flag  string
+		label string
+		value *string
+	}{
+		{"name", "Name", &spec.Name},
+		{"status", "Status (" + strings.Join(model.Statuses, ", ") + ")", &spec.Status},
+		{"location", "Location", &spec.Location},
+		{"language", "Language", &spec.Language},
+		{"description", "Description", &spec.Description},
+	}
+	for _, field := range fields {
+		if !asked(field.flag) {
+			continue
+		}
+		answer, err := p.ask(field.label, *field.value)
+		if err != nil {
+			return err
+		}
+		*field.value = answer
+	}
+
+	lists := []struct {
+		flag  string
+		label string
+		value *[]string
+	}{
+		{"depends-on", "Depends on", &spec.DependsOn},
+		{"contracts", "Implements contracts", &spec.Contracts},
+	}
+	for _, list := range lists {
+		if !asked(list.flag) {
+			continue
+		}
+		answer, err := p.askList(list.label, *list.value)
+		if err != nil {
+			return err
+		}
+		*list.value = answer
+	}
+	return nil
+}
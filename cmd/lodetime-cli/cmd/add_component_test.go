@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/validate"
+)
+
+func TestRenderComponentYAMLValidates(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"config.yaml":                   "project: fixture\nschema_version: 1\ncurrent_phase: 1\nzones:\n  core:\n    paths: [lib/]\n",
+		"components/config-loader.yaml": "id: config-loader\nschema_version: 1\nname: Config Loader\nstatus: planned\nlocation: lib/config/\ndepends_on: []\n",
+		"contracts/graph-api.yaml":      "id: graph-api\nschema_version: 1\nname: Graph API\ndescription: d\n",
+	})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	spec := componentSpec{
+		ID:          "graph-server",
+		Name:        defaultComponentName("graph-server"),
+		Status:      "planned",
+		Location:    "lib/graph/",
+		Language:    "elixir",
+		Description: "Holds: the graph",
+		DependsOn:   []string{"config-loader"},
+		Contracts:   []string{"graph-api"},
+	}
+	if err := checkComponentSpec(project, spec); err != nil {
+		t.Fatalf("checkComponentSpec error: %v", err)
+	}
+
+	got := renderComponentYAML(spec)
+	want := "id: graph-server\nschema_version: 1\nname: Graph Server\nstatus: planned\nlanguage: elixir\n" +
+		"description: 'Holds: the graph'\nlocation: lib/graph/\ndepends_on: [config-loader]\nimplements_contracts: [graph-api]\n"
+	if got != want {
+		t.Fatalf("unexpected YAML:\n%s", got)
+	}
+
+	writeFiles(t, lodeDir, map[string]string{"components/graph-server.yaml": got})
+	if report := validate.Dir(lodeDir); len(report.Findings) != 0 {
+		t.Fatalf("unexpected findings: %+v", report.Findings)
+	}
+}
+
+func TestCheckComponentSpecRejectsBadReferences(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"components/config-loader.yaml": "id: config-loader\nlocation: lib/config/\ndepends_on: []\n",
+	})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	valid := componentSpec{ID: "graph-server", Name: "Graph Server", Status: "planned", Location: "lib/graph/"}
+	cases := map[string]func(*componentSpec){
+		"invalid component id":            func(s *componentSpec) { s.ID = "Graph_Server" },
+		"component config-loader already": func(s *componentSpec) { s.ID = "config-loader" },
+		"invalid status":                  func(s *componentSpec) { s.Status = "done" },
+		"location is required":            func(s *componentSpec) { s.Location = "" },
+		"unknown depends_on component(s): ghost, graph-server": func(s *componentSpec) {
+			s.DependsOn = []string{"config-loader", "ghost", "graph-server"}
+		},
+		"unknown contract(s): api": func(s *componentSpec) { s.Contracts = []string{"api"} },
+	}
+	for want, mutate := range cases {
+		spec := valid
+		mutate(&spec)
+		if err := checkComponentSpec(project, spec); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+	}
+}
+
+func TestInsertBuildOrder(t *testing.T) {
+	block := "project: demo\n# order matters\nbuild_order:\n  - a\n  - b  # last\n\ntriggers: {}\n"
+	got, err := insertBuildOrder([]byte(block), "c", []string{"a"})
+	if err != nil {
+		t.Fatalf("insertBuildOrder error: %v", err)
+	}
+	if want := "project: demo\n# order matters\nbuild_order:\n  - a\n  - b  # last\n  - c\n\ntriggers: {}\n"; string(got) != want {
+		t.Fatalf("unexpected block edit:\n%s", got)
+	}
+
+	got, err = insertBuildOrder([]byte("build_order: [a, b] # comment\nzones: {}"), "c", []string{"a", "b"})
+	if err != nil || string(got) != "build_order: [a, b, c] # comment\nzones: {}" {
+		t.Fatalf("unexpected flow edit: %q, %v", got, err)
+	}
+	got, err = insertBuildOrder([]byte("build_order: []\n"), "c", nil)
+	if err != nil || string(got) != "build_order: [c]\n" {
+		t.Fatalf("unexpected empty edit: %q, %v", got, err)
+	}
+
+	for config, want := range map[string]string{
+		"project: demo\n":             "no build_order",
+		"build_order: [a, c]\n":       "already lists c",
+		"build_order:\n  [a,\n  b]\n": "spans several lines",
+		"build_order: [a]\n":          "does not list dependency b",
+	} {
+		if _, err := insertBuildOrder([]byte(config), "c", []string{"b"}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q for %q, got %v", want, config, err)
+		}
+	}
+}
+
+func TestPrompterComponentSpec(t *testing.T) {
+	spec := componentSpec{ID: "graph-server", Name: "Graph Server", Status: "planned", DependsOn: []string{"x"}}
+	in := strings.NewReader("\nimplemented\nlib/graph/\n\n\nconfig-loader, state-server\n")
+	var out bytes.Buffer
+
+	// --depends-on was given, so it is not asked for.
+	asked := func(flag string) bool { return flag != "depends-on" }
+	if err := newPrompter(in, &out).componentSpec(&spec, asked); err != nil {
+		t.Fatalf("componentSpec error: %v", err)
+	}
+	if spec.Name != "Graph Server" || spec.Status != "implemented" || spec.Location != "lib/graph/" {
+		t.Fatalf("unexpected spec: %+v", spec)
+	}
+	if strings.Join(spec.DependsOn, ",") != "x" || strings.Join(spec.Contracts, ",") != "config-loader,state-server" {
+		t.Fatalf("unexpected lists: %+v", spec)
+	}
+	if !strings.Contains(out.String(), "Name [Graph Server]: ") || strings.Contains(out.String(), "Depends on") {
+		t.Fatalf("unexpected prompts: %q", out.String())
+	}
+}
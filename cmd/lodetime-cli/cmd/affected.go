@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	affectedModes   modeFlags
+	affectedGitDiff string
+	affectedJSON    bool
+)
+
+var affectedCmd = &cobra.Command{
+	Use:   "affected [component|path]...",
+	Short: "Show components affected by a change",
+	Long: `Maps changed files to components by their location and expands the
+result to every component that depends on them, directly or transitively.
+
+Arguments may be component IDs or file paths. With --git-diff, the changed
+files come from 'git diff --name-only <rev>'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+		projectRoot := filepath.Dir(lodeDir)
+
+		mode, err := affectedModes.resolve()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if len(args) == 0 && affectedGitDiff == "" {
+			fmt.Fprintln(os.Stderr, "Provide component IDs, paths, or --git-diff <rev>")
+			os.Exit(1)
+		}
+
+		components, err := loadComponents(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load components:", err)
+			os.Exit(1)
+		}
+
+		var paths []string
+		if affectedGitDiff != "" {
+			paths, err = gitDiffFiles(projectRoot, affectedGitDiff)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		report := resolveChanges(components, projectRoot, args, paths)
+
+		affected := []string{}
+		offline, err := queryRuntime(lodeDir, mode, "affected", func(ctx context.Context, c *client.Client) error {
+			for _, id := range report.Changed {
+				result, err := c.Affected(ctx, id)
+				if err != nil {
+					return err
+				}
+				affected = append(affected, id)
+				affected = append(affected, result.Affected...)
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Connected affected failed:", err)
+			os.Exit(1)
+		}
+
+		if offline {
+			report.Mode = string(modeOffline)
+			report.Affected = affectedComponents(components, report.Changed)
+		} else {
+			report.Mode = string(modeConnected)
+			report.Affected = uniqueSorted(affected)
+		}
+
+		if affectedJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Print(renderAffectedHuman(report))
+	},
+}
+
+func init() {
+	addModeFlags(affectedCmd, &affectedModes)
+	affectedCmd.Flags().StringVar(&affectedGitDiff, "git-diff", "", "use files changed since this git revision")
+	affectedCmd.Flags().BoolVar(&affectedJSON, "json", false, "output JSON only")
+}
+
+type affectedReport struct {
+	Mode     string   `json:"mode"`
+	Changed  []string `json:"changed"`
+	Affected []string `json:"affected"`
+	Unmapped []string `json:"unmapped,omitempty"`
+}
+
+// resolveChanges turns arguments (component IDs or paths relative to the
+// working directory) and project-relative paths into changed component IDs.
+// Paths no component owns are reported as unmapped.
+func resolveChanges(components []client.Component, projectRoot string, args, projectPaths []string) affectedReport {
+	known := map[string]bool{}
+	for _, component := range components {
+		known[component.ID] = true
+	}
+
+	changed := []string{}
+	unmapped := []string{}
+	mapPath := func(rel string) {
+		if id, ok := componentForPath(components, rel); ok {
+			changed = append(changed, id)
+		} else {
+			unmapped = append(unmapped, rel)
+		}
+	}
+
+	for _, arg := range args {
+		if known[arg] {
+			changed = append(changed, arg)
+			continue
+		}
+		mapPath(projectRelativePath(projectRoot, arg))
+	}
+	for _, path := range projectPaths {
+		mapPath(filepath.ToSlash(path))
+	}
+
+	return affectedReport{
+		Changed:  uniqueSorted(changed),
+		Unmapped: uniqueSorted(unmapped),
+	}
+}
+
+// projectRelativePath converts a path given on the command line to a
+// slash-separated path relative to the project root.
+func projectRelativePath(projectRoot, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(projectRoot, abs)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// componentForPath returns the component whose location contains path. When
+// locations nest, the most specific one wins.
+func componentForPath(components []client.Component, path string) (string, bool) {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+
+	best, bestLen := "", -1
+	for _, component := range components {
+		location := strings.TrimSuffix(strings.TrimPrefix(component.Location, "./"), "/")
+		if location == "" {
+			continue
+		}
+		if path != location && !strings.HasPrefix(path, location+"/") {
+			continue
+		}
+		if len(location) > bestLen {
+			best, bestLen = component.ID, len(location)
+		}
+	}
+
+	return best, bestLen >= 0
+}
+
+// affectedComponents returns changed plus every component that transitively
+// depends on one of them, sorted.
+func affectedComponents(components []client.Component, changed []string) []string {
+	dependents := map[string][]string{}
+	for _, component := range components {
+		for _, dep := range component.DependsOn {
+			dependents[dep] = append(dependents[dep], component.ID)
+		}
+	}
+
+	seen := map[string]bool{}
+	queue := append([]string{}, changed...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		queue = append(queue, dependents[id]...)
+	}
+
+	affected := make([]string, 0, len(seen))
+	for id := range seen {
+		affected = append(affected, id)
+	}
+	sort.Strings(affected)
+	return affected
+}
+
+func uniqueSorted(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+func renderAffectedHuman(report affectedReport) string {
+	builder := &strings.Builder{}
+
+	fmt.Fprintln(builder, "Affected Components")
+	fmt.Fprintf(builder, "Mode: %s\n", report.Mode)
+	fmt.Fprintf(builder, "Changed: %s\n", formatList(report.Changed))
+
+	fmt.Fprintln(builder)
+	fmt.Fprintf(builder, "Affected (%d)\n", len(report.Affected))
+	for _, id := range report.Affected {
+		fmt.Fprintf(builder, "  %s\n", id)
+	}
+
+	if len(report.Unmapped) > 0 {
+		fmt.Fprintln(builder)
+		fmt.Fprintf(builder, "Unmapped Paths (%d)\n", len(report.Unmapped))
+		for _, path := range report.Unmapped {
+			fmt.Fprintf(builder, "  %s\n", path)
+		}
+	}
+
+	return builder.String()
+}
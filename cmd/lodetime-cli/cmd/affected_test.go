@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/client"
+)
+
+func affectedFixture() []client.Component {
+	return []client.Component{
+		{ID: "cli", Location: "cmd/lodetime-cli/", DependsOn: []string{"cli-socket"}},
+		{ID: "cli-socket", Location: "lib/lodetime/interface/cli_socket/", DependsOn: []string{"graph-server"}},
+		{ID: "config-loader", Location: "lib/lodetime/config/"},
+		{ID: "graph-server", Location: "lib/lodetime/graph/", DependsOn: []string{"config-loader"}},
+		{ID: "interface", Location: "lib/lodetime/interface/"},
+	}
+}
+
+func TestComponentForPathPrefersMostSpecificLocation(t *testing.T) {
+	components := affectedFixture()
+
+	cases := map[string]string{
+		"lib/lodetime/interface/cli_socket/handler.ex": "cli-socket",
+		"lib/lodetime/interface/cli_stdio.ex":          "interface",
+		"./lib/lodetime/graph/server.ex":               "graph-server",
+	}
+	for path, want := range cases {
+		got, ok := componentForPath(components, path)
+		if !ok || got != want {
+			t.Fatalf("componentForPath(%q) = %q, %v; want %q", path, got, ok, want)
+		}
+	}
+
+	if _, ok := componentForPath(components, "lib/lodetime/graphite.ex"); ok {
+		t.Fatalf("expected no match for sibling prefix")
+	}
+}
+
+func TestResolveChangesExpandsToDependents(t *testing.T) {
+	components := affectedFixture()
+	projectRoot := t.TempDir()
+
+	report := resolveChanges(components, projectRoot,
+		[]string{"config-loader", filepath.Join(projectRoot, "README.md")},
+		[]string{"lib/lodetime/graph/server.ex"},
+	)
+	if strings.Join(report.Changed, ",") != "config-loader,graph-server" {
+		t.Fatalf("unexpected changed: %v", report.Changed)
+	}
+	if strings.Join(report.Unmapped, ",") != "README.md" {
+		t.Fatalf("unexpected unmapped: %v", report.Unmapped)
+	}
+
+	affected := affectedComponents(components, report.Changed)
+	if strings.Join(affected, ",") != "cli,cli-socket,config-loader,graph-server" {
+		t.Fatalf("unexpected affected: %v", affected)
+	}
+}
+
+func TestGitDiffFilesRejectsOptionRevs(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "x")
+	if _, err := gitDiffFiles(dir, "--output="+output); err == nil || !strings.Contains(err.Error(), "invalid git revision") {
+		t.Fatalf("expected invalid revision error, got %v", err)
+	}
+	if fileExists(output) {
+		t.Fatalf("git wrote %s", output)
+	}
+}
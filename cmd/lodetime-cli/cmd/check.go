@@ -1,35 +1,373 @@
 package cmd
 
 import (
-	"errors"
+	"context"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"os"
-	"time"
+	"path/filepath"
+	"strings"
 
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/lodetime/lodetime-cli/validate"
 	"github.com/spf13/cobra"
 )
 
+// Exit codes of lode check. Warnings only fail with --fail-on warn and
+// errors not at all with --fail-on block.
+const (
+	checkExitOK     = 0
+	checkExitFailed = 1 // the check could not run
+	checkExitWarn   = 2
+	checkExitError  = 3
+	checkExitBlock  = 4
+)
+
+var (
+	checkModes     modeFlags
+	checkFailOn    string
+	checkJSON      bool
+	checkJUnit     string
+	checkStaged    bool
+	checkSkipTests bool
+)
+
 var checkCmd = &cobra.Command{
 	Use:   "check",
-	Short: "Check runtime health",
+	Short: "Validate the project as a pre-commit or CI gate",
+	Long: `Validates the project through the runtime, or offline with the same rules as
+lode validate when no runtime is reachable. Rule severities can be overridden
+under rules: in config.yaml.
+
+Severities: info is FYI, warn is visible but passes, error fails the check and
+block fails it with its own exit code. --fail-on lowers or raises the
+threshold.
+
+Exit codes: 0 pass, 1 check could not run, 2 warn (with --fail-on warn),
+3 error, 4 block.
+
+--staged limits the check to what is about to be committed: the staged
+.lodetime/ files are validated offline, so unstaged edits do not change the
+result. Staged files are mapped to components by location, only findings for
+those components and their dependents are kept, and a yaml-drift warning is
+added for each component whose code is staged without its
+.lodetime/components/*.yaml. The tests those components declare are then run
+from the working tree (mix test for .exs files, go test for the package of a
+_test.go file; output goes to stderr) and each failing run is a test-failure
+finding; --skip-tests only lists them. It is skipped only when
+triggers.git.on_stage is set to false.
+
+--junit writes a JUnit XML report with one test case per rule to the given
+file ("-" for stdout, which cannot be combined with --json).`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		lodeDir := findLodeTimeRoot()
 		if lodeDir == "" {
 			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
-			os.Exit(1)
+			os.Exit(checkExitFailed)
+		}
+
+		if checkJSON && checkJUnit == "-" {
+			fmt.Fprintln(os.Stderr, "--json and --junit - both write to stdout; write the JUnit report to a file instead")
+			os.Exit(checkExitFailed)
 		}
 
-		endpoint := resolveEndpoint(runtimeEndpoint, lodeDir)
-		_, err := fetchStatus(endpoint, false, time.Second)
+		failOn, err := validate.ParseSeverity(checkFailOn)
+		if err != nil || failOn == validate.SeverityInfo {
+			fmt.Fprintf(os.Stderr, "invalid --fail-on %q (expected warn, error or block)\n", checkFailOn)
+			os.Exit(checkExitFailed)
+		}
+
+		mode, err := checkModes.resolve()
 		if err != nil {
-			if errors.Is(err, errConnect) {
-				fmt.Fprintln(os.Stderr, "Runtime not reachable:", err)
-			} else {
-				fmt.Fprintln(os.Stderr, "Runtime check failed:", err)
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(checkExitFailed)
+		}
+
+		var scope *stagedScope
+		var project *model.Project
+		checkDir := lodeDir
+		cleanup := func() {}
+		if checkStaged {
+			if mode == modeConnected {
+				fmt.Fprintln(os.Stderr, "--staged validates the index offline and cannot be combined with --connected")
+				os.Exit(checkExitFailed)
+			}
+			mode = modeOffline
+
+			staged, err := gitStagedFiles(filepath.Dir(lodeDir))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(checkExitFailed)
+			}
+			checkDir, cleanup, err = checkoutStagedLodeDir(lodeDir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to read staged .lodetime/:", err)
+				os.Exit(checkExitFailed)
+			}
+
+			// A broken .lodetime/ cannot be mapped; it is then checked in full.
+			project, _ = model.Load(checkDir)
+			if project != nil && !project.Config.Triggers.Git.StageChecks() {
+				cleanup()
+				fmt.Fprintln(os.Stderr, "Skipping staged check: triggers.git.on_stage is false in .lodetime/config.yaml")
+				return
 			}
-			os.Exit(1)
+			if project != nil {
+				scope = newStagedScope(project, staged)
+			}
+		}
+
+		var paths []string
+		if scope != nil {
+			paths = scope.Staged
+		}
+
+		var findings []validate.Finding
+		offline, err := queryRuntime(lodeDir, mode, "validate", func(ctx context.Context, c *client.Client) error {
+			remote, err := c.Validate(ctx, paths)
+			findings = fromClientFindings(remote)
+			return err
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Connected check failed:", err)
+			os.Exit(checkExitFailed)
+		}
+		if offline {
+			findings = validate.DirIn(checkDir, filepath.Dir(lodeDir)).Findings
+		}
+		if scope != nil {
+			findings = append(scope.filter(findings), scope.drift(project)...)
+		}
+		cleanup()
+
+		if scope != nil && !checkSkipTests {
+			root := filepath.Dir(lodeDir)
+			runs, unknown := testRuns(root, scope.Tests)
+			severity := validate.RuleSeverity(&project.Config, validate.RuleTestFailure, validate.SeverityError)
+			findings = append(findings, runTests(root, runs, unknown, severity, os.Stderr)...)
+			scope.TestsRun = true
 		}
 
-		fmt.Println("Runtime reachable.")
+		result := newCheckResult(findings, failOn, offline)
+		result.Scope = scope
+
+		if checkJUnit != "" {
+			output, err := renderCheckJUnit(result)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JUnit:", err)
+				os.Exit(checkExitFailed)
+			}
+			if checkJUnit == "-" {
+				fmt.Print(output)
+			} else if err := os.WriteFile(checkJUnit, []byte(output), 0o644); err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to write JUnit report:", err)
+				os.Exit(checkExitFailed)
+			}
+		}
+
+		if checkJSON {
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(checkExitFailed)
+			}
+			fmt.Println(string(data))
+		} else if checkJUnit != "-" {
+			fmt.Print(renderCheckHuman(result))
+		}
+
+		if result.ExitCode != checkExitOK {
+			os.Exit(result.ExitCode)
+		}
 	},
 }
+
+func init() {
+	addModeFlags(checkCmd, &checkModes)
+	checkCmd.Flags().StringVar(&checkFailOn, "fail-on", string(validate.SeverityError), "lowest severity that fails the check: warn, error or block")
+	checkCmd.Flags().BoolVar(&checkJSON, "json", false, "output JSON only")
+	checkCmd.Flags().StringVar(&checkJUnit, "junit", "", "write a JUnit XML report to this file (- for stdout)")
+	checkCmd.Flags().BoolVar(&checkStaged, "staged", false, "check only components touched by staged files (used by the pre-commit hook)")
+	checkCmd.Flags().BoolVar(&checkSkipTests, "skip-tests", false, "with --staged, list the declared tests instead of running them")
+}
+
+type checkResult struct {
+	OK       bool                      `json:"ok"`
+	Mode     statusMode                `json:"mode"`
+	FailOn   validate.Severity         `json:"fail_on"`
+	ExitCode int                       `json:"exit_code"`
+	Counts   map[validate.Severity]int `json:"counts"`
+	Findings []validate.Finding        `json:"findings"`
+	// Scope is set for --staged.
+	Scope *stagedScope `json:"scope,omitempty"`
+}
+
+func newCheckResult(findings []validate.Finding, failOn validate.Severity, offline bool) checkResult {
+	result := checkResult{
+		OK:       true,
+		Mode:     modeConnected,
+		FailOn:   failOn,
+		Counts:   map[validate.Severity]int{},
+		Findings: findings,
+	}
+	if offline {
+		result.Mode = modeOffline
+	}
+	if result.Findings == nil {
+		result.Findings = []validate.Finding{}
+	}
+	for _, severity := range validate.Severities {
+		result.Counts[severity] = 0
+	}
+
+	for _, finding := range result.Findings {
+		result.Counts[finding.Severity]++
+	}
+
+	worst := (&validate.Report{Findings: result.Findings}).Worst()
+	if worst == "" || !worst.AtLeast(failOn) {
+		return result
+	}
+	result.OK = false
+	switch worst {
+	case validate.SeverityBlock:
+		result.ExitCode = checkExitBlock
+	case validate.SeverityError:
+		result.ExitCode = checkExitError
+	default:
+		result.ExitCode = checkExitWarn
+	}
+	return result
+}
+
+func fromClientFindings(findings []client.Finding) []validate.Finding {
+	out := make([]validate.Finding, 0, len(findings))
+	for _, finding := range findings {
+		out = append(out, validate.Finding{
+			Rule:     finding.Rule,
+			Severity: validate.Severity(finding.Severity),
+			File:     finding.File,
+			Line:     finding.Line,
+			Message:  finding.Message,
+		})
+	}
+	return out
+}
+
+func renderCheckHuman(result checkResult) string {
+	builder := &strings.Builder{}
+
+	counts := make([]string, 0, len(validate.Severities))
+	for i := len(validate.Severities) - 1; i >= 0; i-- {
+		severity := validate.Severities[i]
+		counts = append(counts, fmt.Sprintf("%d %s", result.Counts[severity], severity))
+	}
+
+	verdict := "PASS"
+	if !result.OK {
+		verdict = "FAIL"
+	}
+	fmt.Fprintf(builder, "%s: lode check (%s, fail-on %s)\n", verdict, result.Mode, result.FailOn)
+	fmt.Fprintf(builder, "  %s\n", strings.Join(counts, ", "))
+	if result.Scope != nil {
+		renderStagedScope(builder, result.Scope)
+	}
+
+	for _, finding := range result.Findings {
+		fmt.Fprintf(builder, "  - %s [%s]\n", formatCheckFinding(finding), finding.Rule)
+	}
+
+	return builder.String()
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// renderCheckJUnit reports one test case per rule. Findings at or above
+// --fail-on fail the case; lower ones are kept as system-out.
+func renderCheckJUnit(result checkResult) (string, error) {
+	rules := append([]string{}, validate.Rules...)
+	byRule := map[string][]validate.Finding{}
+	for _, finding := range result.Findings {
+		if _, ok := byRule[finding.Rule]; !ok && !containsString(rules, finding.Rule) {
+			rules = append(rules, finding.Rule)
+		}
+		byRule[finding.Rule] = append(byRule[finding.Rule], finding)
+	}
+
+	suite := junitSuite{Name: "lodetime"}
+	for _, rule := range rules {
+		testCase := junitCase{Name: rule, ClassName: "lodetime.check"}
+
+		var failing, passing []string
+		var worst validate.Severity
+		for _, finding := range byRule[rule] {
+			line := formatCheckFinding(finding)
+			if finding.Severity.AtLeast(result.FailOn) {
+				failing = append(failing, line)
+				if finding.Severity.Rank() > worst.Rank() {
+					worst = finding.Severity
+				}
+			} else {
+				passing = append(passing, line)
+			}
+		}
+
+		if len(failing) > 0 {
+			testCase.Failure = &junitFailure{
+				Type:    string(worst),
+				Message: fmt.Sprintf("%d finding(s)", len(failing)),
+				Text:    strings.Join(failing, "\n"),
+			}
+			suite.Failures++
+		}
+		if len(passing) > 0 {
+			testCase.SystemOut = strings.Join(passing, "\n")
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+
+	suites := junitSuites{Name: "lode check", Tests: suite.Tests, Failures: suite.Failures, Suites: []junitSuite{suite}}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+func formatCheckFinding(finding validate.Finding) string {
+	location := finding.Location()
+	if location != "" {
+		location += ": "
+	}
+	return fmt.Sprintf("%s: %s%s", finding.Severity, location, finding.Message)
+}
@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/lodetime/lodetime-cli/validate"
 )
 
 func TestCheckCommandSuccess(t *testing.T) {
@@ -33,12 +35,12 @@ func TestCheckCommandSuccess(t *testing.T) {
 			done <- err
 			return
 		}
-		if !strings.Contains(line, "\"cmd\":\"status\"") {
-			done <- fmt.Errorf("expected status command, got: %s", strings.TrimSpace(line))
+		if !strings.Contains(line, "\"cmd\":\"validate\"") {
+			done <- fmt.Errorf("expected validate command, got: %s", strings.TrimSpace(line))
 			return
 		}
 
-		response := `{"ok":true,"data":{"mode":"connected"}}`
+		response := `{"ok":true,"data":{"findings":[{"rule":"build-order","severity":"warn","file":"config.yaml","line":3,"message":"missing x"}]}}`
 		_, _ = conn.Write([]byte(response + "\n"))
 		done <- nil
 	}()
@@ -81,11 +83,60 @@ func TestCheckCommandSuccess(t *testing.T) {
 	if err != nil {
 		t.Fatalf("read output: %v", err)
 	}
-	if !strings.Contains(string(output), "Runtime reachable.") {
-		t.Fatalf("expected success output, got: %s", string(output))
+	for _, want := range []string{"PASS: lode check (connected, fail-on error)", "0 block, 0 error, 1 warn, 0 info", "warn: config.yaml:3: missing x [build-order]"} {
+		if !strings.Contains(string(output), want) {
+			t.Fatalf("expected %q in output, got: %s", want, string(output))
+		}
 	}
 
 	if err := <-done; err != nil {
 		t.Fatalf("server error: %v", err)
 	}
 }
+
+func TestCheckResultExitCodes(t *testing.T) {
+	warn := validate.Finding{Rule: validate.RuleBuildOrder, Severity: validate.SeverityWarn, Message: "w"}
+	failure := validate.Finding{Rule: validate.RuleUnknownDependency, Severity: validate.SeverityError, Message: "e"}
+	block := validate.Finding{Rule: validate.RuleCircularDeps, Severity: validate.SeverityBlock, Message: "b"}
+
+	cases := []struct {
+		findings []validate.Finding
+		failOn   validate.Severity
+		want     int
+	}{
+		{nil, validate.SeverityError, checkExitOK},
+		{[]validate.Finding{warn}, validate.SeverityError, checkExitOK},
+		{[]validate.Finding{warn}, validate.SeverityWarn, checkExitWarn},
+		{[]validate.Finding{warn, failure}, validate.SeverityWarn, checkExitError},
+		{[]validate.Finding{failure}, validate.SeverityBlock, checkExitOK},
+		{[]validate.Finding{failure, block}, validate.SeverityError, checkExitBlock},
+	}
+	for _, tc := range cases {
+		result := newCheckResult(tc.findings, tc.failOn, true)
+		if result.ExitCode != tc.want || result.OK != (tc.want == checkExitOK) {
+			t.Fatalf("findings %v fail-on %s: exit %d ok %v, want %d", tc.findings, tc.failOn, result.ExitCode, result.OK, tc.want)
+		}
+	}
+}
+
+func TestRenderCheckJUnit(t *testing.T) {
+	result := newCheckResult([]validate.Finding{
+		{Rule: validate.RuleUnknownDependency, Severity: validate.SeverityError, File: "components/a.yaml", Line: 4, Message: "unknown dependency `b`"},
+		{Rule: validate.RuleBuildOrder, Severity: validate.SeverityWarn, File: "config.yaml", Message: "missing a"},
+	}, validate.SeverityError, true)
+
+	output, err := renderCheckJUnit(result)
+	if err != nil {
+		t.Fatalf("renderCheckJUnit error: %v", err)
+	}
+	for _, want := range []string{
+		fmt.Sprintf(`<testsuites name="lode check" tests="%d" failures="1">`, len(validate.Rules)),
+		`<testcase name="unknown-dependency" classname="lodetime.check">`,
+		`<failure type="error" message="1 finding(s)">error: components/a.yaml:4: unknown dependency `,
+		`<system-out>warn: config.yaml: missing a</system-out>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in JUnit output:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	componentModes modeFlags
+	componentJSON  bool
+)
+
+var componentCmd = &cobra.Command{
+	Use:   "component [id]",
+	Short: "Show component details",
+	Long: `Shows a component's spec. When the runtime is connected it also shows
+live state: health, the last test result, and dependents.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		mode, err := componentModes.resolve()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		id := args[0]
+		var component *client.Component
+		offline, err := queryRuntime(lodeDir, mode, "component", func(ctx context.Context, c *client.Client) error {
+			var err error
+			component, err = c.Component(ctx, id)
+			return err
+		})
+		if err != nil {
+			if client.IsCode(err, client.CodeNotFound) {
+				fmt.Fprintf(os.Stderr, "Component not found: %s\n", id)
+			} else {
+				fmt.Fprintln(os.Stderr, "Connected component failed:", err)
+			}
+			os.Exit(1)
+		}
+
+		if offline {
+			component, err = buildOfflineComponent(lodeDir, id)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Offline component failed:", err)
+				os.Exit(1)
+			}
+			if component == nil {
+				fmt.Fprintf(os.Stderr, "Component not found: %s\n", id)
+				os.Exit(1)
+			}
+		}
+
+		// The runtime's (or the spec's) language wins; the local tree is only
+		// consulted when neither gives one, as in lode list.
+		if component.Language == "" {
+			component.Language = componentLanguage(filepath.Dir(lodeDir), *component)
+		}
+
+		if componentJSON {
+			output, err := renderComponentJSON(component, offline)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+			return
+		}
+
+		fmt.Print(renderComponentHuman(component, offline))
+	},
+}
+
+func init() {
+	addModeFlags(componentCmd, &componentModes)
+	componentCmd.Flags().BoolVar(&componentJSON, "json", false, "output JSON only")
+}
+
+// buildOfflineComponent returns the spec for id with dependents derived from
+// the other specs, or nil if there is no such component.
+func buildOfflineComponent(lodeDir, id string) (*client.Component, error) {
+	components, err := loadComponents(lodeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *client.Component
+	dependents := []string{}
+	for i := range components {
+		if components[i].ID == id {
+			found = &components[i]
+			continue
+		}
+		for _, dep := range components[i].DependsOn {
+			if dep == id {
+				dependents = append(dependents, components[i].ID)
+				break
+			}
+		}
+	}
+
+	if found != nil {
+		found.Dependents = dependents
+	}
+
+	return found, nil
+}
+
+func renderComponentJSON(component *client.Component, offline bool) (string, error) {
+	view := struct {
+		Mode   string `json:"mode"`
+		Source string `json:"source,omitempty"`
+		*client.Component
+	}{
+		Mode:      string(modeConnected),
+		Component: component,
+	}
+	if offline {
+		view.Mode = string(modeOffline)
+		view.Source = "offline"
+	}
+
+	data, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func renderComponentHuman(component *client.Component, offline bool) string {
+	builder := &strings.Builder{}
+
+	fmt.Fprintf(builder, "Component: %s\n", component.ID)
+	if offline {
+		fmt.Fprintf(builder, "Mode: %s\n", modeOffline)
+	} else {
+		fmt.Fprintf(builder, "Mode: %s\n", modeConnected)
+	}
+
+	writeSection(builder, "Spec", []sectionField{
+		{key: "name", value: component.Name},
+		{key: "status", value: component.Status},
+		{key: "language", value: component.Language},
+		{key: "location", value: component.Location},
+		{key: "description", value: component.Description},
+		{key: "depends_on", value: formatList(component.DependsOn)},
+		{key: "dependents", value: formatList(component.Dependents)},
+		{key: "implements_contracts", value: formatList(component.ImplementsContracts)},
+		{key: "tests", value: formatList(component.Tests)},
+	})
+
+	if !offline {
+		fields := []sectionField{{key: "health", value: component.Health}}
+		if test := component.LastTest; test != nil {
+			fields = append(fields,
+				sectionField{key: "last_test", value: test.Status},
+				sectionField{key: "last_test_at", value: test.At},
+				sectionField{key: "passed", value: test.Passed},
+				sectionField{key: "failed", value: test.Failed},
+			)
+		} else {
+			fields = append(fields, sectionField{key: "last_test", value: nil})
+		}
+		writeSection(builder, "Live State", fields)
+	}
+
+	return builder.String()
+}
+
+func formatList(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/client"
+)
+
+// writeLodeFixture creates a .lodetime/ tree from relative path → content and
+// returns its path. A minimal config.yaml is added unless one is given.
+func writeLodeFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	lodeDir := filepath.Join(t.TempDir(), ".lodetime")
+	for _, dir := range []string{"components", "contracts"} {
+		if err := os.MkdirAll(filepath.Join(lodeDir, dir), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	if _, ok := files["config.yaml"]; !ok {
+		files["config.yaml"] = "project: fixture\nschema_version: 1\n"
+	}
+	writeFiles(t, lodeDir, files)
+
+	return lodeDir
+}
+
+// writeFiles writes files, keyed by slash-separated path, under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+func TestBuildOfflineComponentFindsDependents(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"components/config-loader.yaml": "id: config-loader\nstatus: implemented\ndepends_on: []\n",
+		"components/graph-server.yaml":  "id: graph-server\nstatus: implemented\ndepends_on: [config-loader]\n",
+		"components/cli-socket.yaml":    "id: cli-socket\nstatus: implemented\ndepends_on: [graph-server]\n",
+	})
+
+	component, err := buildOfflineComponent(lodeDir, "config-loader")
+	if err != nil {
+		t.Fatalf("buildOfflineComponent error: %v", err)
+	}
+	if component == nil {
+		t.Fatalf("expected config-loader component")
+	}
+	if strings.Join(component.Dependents, ",") != "graph-server" {
+		t.Fatalf("expected dependents [graph-server], got %v", component.Dependents)
+	}
+
+	missing, err := buildOfflineComponent(lodeDir, "nope")
+	if err != nil {
+		t.Fatalf("buildOfflineComponent error: %v", err)
+	}
+	if missing != nil {
+		t.Fatalf("expected nil for unknown component, got %+v", missing)
+	}
+}
+
+func TestRenderComponentHumanConnectedShowsLiveState(t *testing.T) {
+	component := &client.Component{
+		ID:         "graph-server",
+		Status:     "implemented",
+		DependsOn:  []string{"config-loader"},
+		Health:     "healthy",
+		LastTest:   &client.TestResult{Status: "passed", Passed: 4},
+		Dependents: []string{"cli-socket"},
+	}
+
+	output := renderComponentHuman(component, false)
+	for _, want := range []string{"Mode: connected", "depends_on: config-loader", "health: healthy", "last_test: passed", "dependents: cli-socket"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	offline := renderComponentHuman(component, true)
+	if strings.Contains(offline, "Live State") {
+		t.Fatalf("expected offline output without live state, got: %s", offline)
+	}
+}
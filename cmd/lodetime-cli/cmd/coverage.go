@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// trackingFull marks zones whose every file should belong to a component.
+const trackingFull = "full"
+
+var coverageSpecJSON bool
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Report how much of the tree the spec covers",
+}
+
+var coverageSpecCmd = &cobra.Command{
+	Use:   "spec [zone]...",
+	Short: "Find files no component owns",
+	Long: `Walks the paths of every zone with tracking: full, maps each file to a
+component by location, and reports orphan files and directories together with
+the share of files each zone's components cover.
+
+Files come from git (tracked plus untracked, ignored files excluded) or, outside
+a git repository, from the file system without hidden and dependency
+directories.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+		for _, zone := range args {
+			if _, ok := project.Config.Zones[zone]; !ok {
+				fmt.Fprintf(os.Stderr, "unknown zone: %s\n", zone)
+				os.Exit(1)
+			}
+		}
+
+		report, err := buildSpecCoverage(filepath.Dir(lodeDir), project, args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if coverageSpecJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		writeSpecCoverage(os.Stdout, report)
+	},
+}
+
+func init() {
+	coverageSpecCmd.Flags().BoolVar(&coverageSpecJSON, "json", false, "output JSON only")
+	coverageCmd.AddCommand(coverageSpecCmd)
+}
+
+type specCoverage struct {
+	Zones []zoneCoverage `json:"zones"`
+	// Skipped lists zones whose tracking is not full.
+	Skipped []string `json:"skipped"`
+}
+
+type zoneCoverage struct {
+	Zone    string   `json:"zone"`
+	Paths   []string `json:"paths"`
+	Files   int      `json:"files"`
+	Covered int      `json:"covered"`
+	Percent float64  `json:"percent"`
+	// Orphans are files, or directories (ending in /) whose files are all
+	// orphans.
+	Orphans []string `json:"orphans"`
+}
+
+func buildSpecCoverage(projectRoot string, project *model.Project, only []string) (specCoverage, error) {
+	report := specCoverage{Zones: []zoneCoverage{}, Skipped: []string{}}
+	components := clientComponents(project.Components)
+
+	names := make([]string, 0, len(project.Config.Zones))
+	for name := range project.Config.Zones {
+		if len(only) == 0 || containsString(only, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		zone := project.Config.Zones[name]
+		if zone.Tracking != trackingFull {
+			report.Skipped = append(report.Skipped, name)
+			continue
+		}
+
+		files := []string{}
+		for _, root := range zone.Paths {
+			found, err := zoneFiles(projectRoot, root)
+			if err != nil {
+				return report, err
+			}
+			files = append(files, found...)
+		}
+		report.Zones = append(report.Zones, zoneSpecCoverage(name, zone.Paths, uniqueSorted(files), components))
+	}
+	return report, nil
+}
+
+// zoneSpecCoverage maps files to components and folds orphans into the
+// largest directories that contain nothing else.
+func zoneSpecCoverage(name string, roots, files []string, components []client.Component) zoneCoverage {
+	coverage := zoneCoverage{Zone: name, Paths: roots, Files: len(files), Orphans: []string{}}
+
+	total := map[string]int{}
+	orphaned := map[string]int{}
+	orphans := []string{}
+	for _, file := range files {
+		_, owned := componentForPath(components, file)
+		if owned {
+			coverage.Covered++
+		} else {
+			orphans = append(orphans, file)
+		}
+		for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			total[dir]++
+			if !owned {
+				orphaned[dir]++
+			}
+		}
+	}
+	if coverage.Files > 0 {
+		coverage.Percent = float64(coverage.Covered) * 100 / float64(coverage.Files)
+	}
+
+	collapsed := []string{}
+	for _, file := range orphans {
+		entry := file
+		for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if orphaned[dir] != total[dir] {
+				break
+			}
+			entry = dir + "/"
+			if isZoneRoot(dir, roots) {
+				// Never fold past a zone path.
+				break
+			}
+		}
+		collapsed = append(collapsed, entry)
+	}
+	coverage.Orphans = uniqueSorted(collapsed)
+	return coverage
+}
+
+func isZoneRoot(dir string, roots []string) bool {
+	for _, root := range roots {
+		if strings.TrimSuffix(strings.TrimPrefix(root, "./"), "/") == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneFiles lists project-relative files under root, preferring git so
+// ignored files are left out.
+func zoneFiles(projectRoot, root string) ([]string, error) {
+	root = strings.TrimSuffix(strings.TrimPrefix(root, "./"), "/")
+
+	output, err := gitOutput(projectRoot, "ls-files", "--cached", "--others", "--exclude-standard", "--", root)
+	if err == nil {
+		files := []string{}
+		for _, file := range splitLines(output) {
+			// Deleted files stay in the index until the deletion is staged.
+			if _, err := os.Stat(filepath.Join(projectRoot, filepath.FromSlash(file))); err == nil {
+				files = append(files, file)
+			}
+		}
+		return files, nil
+	}
+
+	files := []string{}
+	start := filepath.Join(projectRoot, filepath.FromSlash(root))
+	err = filepath.WalkDir(start, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if current == start {
+				return fs.SkipDir
+			}
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if current != start && (strings.HasPrefix(name, ".") || name == "_build" || name == "deps" || name == "node_modules" || name == "vendor") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(projectRoot, current)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+func writeSpecCoverage(w io.Writer, report specCoverage) {
+	fmt.Fprintln(w, "Spec coverage (zones with tracking: full)")
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ZONE\tPATHS\tFILES\tCOVERED\tCOVERAGE")
+	for _, zone := range report.Zones {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%.1f%%\n", zone.Zone, strings.Join(zone.Paths, ", "), zone.Files, zone.Covered, zone.Percent)
+	}
+	_ = table.Flush()
+
+	for _, zone := range report.Zones {
+		if len(zone.Orphans) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Orphans in %s (%d file(s) not owned by a component)\n", zone.Zone, zone.Files-zone.Covered)
+		for _, orphan := range zone.Orphans {
+			fmt.Fprintf(w, "  %s\n", orphan)
+		}
+	}
+
+	if len(report.Skipped) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Skipped (tracking not full): %s\n", strings.Join(report.Skipped, ", "))
+	}
+}
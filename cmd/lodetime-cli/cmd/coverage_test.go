@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/client"
+)
+
+func TestZoneSpecCoverageFoldsOrphanDirectories(t *testing.T) {
+	components := []client.Component{
+		{ID: "graph", Location: "lib/app/graph/"},
+		{ID: "config", Location: "lib/app/config/loader.ex"},
+	}
+	files := []string{
+		"lib/app/application.ex",
+		"lib/app/config/loader.ex",
+		"lib/app/config/schema.ex",
+		"lib/app/graph/server.ex",
+		"lib/app/web/router.ex",
+		"lib/app/web/views/page.ex",
+	}
+
+	coverage := zoneSpecCoverage("core", []string{"lib/app/"}, files, components)
+	if coverage.Files != 6 || coverage.Covered != 2 {
+		t.Fatalf("unexpected counts: %+v", coverage)
+	}
+	if got := strings.Join(coverage.Orphans, ","); got != "lib/app/application.ex,lib/app/config/schema.ex,lib/app/web/" {
+		t.Fatalf("unexpected orphans: %s", got)
+	}
+
+	// A zone nobody owns is reported as the zone path itself.
+	coverage = zoneSpecCoverage("legacy", []string{"old/"}, []string{"old/a/x.go", "old/b.go"}, components)
+	if got := strings.Join(coverage.Orphans, ","); got != "old/" || coverage.Percent != 0 {
+		t.Fatalf("unexpected legacy coverage: %+v", coverage)
+	}
+}
+
+func TestBuildSpecCoverageSkipsUntrackedZones(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"config.yaml":          "project: fixture\nschema_version: 1\nzones:\n  core:\n    paths: [lib/]\n    tracking: full\n  docs:\n    paths: [docs/]\n    tracking: light\n",
+		"components/core.yaml": "id: core\nlocation: lib/core/\ndepends_on: []\n",
+	})
+	root := filepath.Dir(lodeDir)
+	writeFiles(t, root, map[string]string{"lib/core/a.ex": "x", "lib/core/b.ex": "x", "lib/extra.ex": "x", "docs/x.md": "x"})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	report, err := buildSpecCoverage(root, project, nil)
+	if err != nil {
+		t.Fatalf("buildSpecCoverage error: %v", err)
+	}
+	if len(report.Zones) != 1 || strings.Join(report.Skipped, ",") != "docs" {
+		t.Fatalf("unexpected zones: %+v", report)
+	}
+
+	var out bytes.Buffer
+	writeSpecCoverage(&out, report)
+	for _, want := range []string{"core  lib/   3      2        66.7%", "Orphans in core (1 file(s) not owned by a component)", "  lib/extra.ex", "Skipped (tracking not full): docs"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
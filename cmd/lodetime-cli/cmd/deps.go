@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	depsModes   modeFlags
+	depsDepth   int
+	depsReverse bool
+	depsJSON    bool
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps [id]",
+	Short: "Show a component's dependency tree",
+	Long: `Walks depends_on edges from a component. With --reverse it walks the
+other way and shows everything that depends on the component.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		mode, err := depsModes.resolve()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if depsDepth < 0 {
+			fmt.Fprintln(os.Stderr, "--depth must be 0 (unlimited) or greater")
+			os.Exit(1)
+		}
+
+		id := args[0]
+		var deps *client.Dependencies
+		offline, err := queryRuntime(lodeDir, mode, "dependencies", func(ctx context.Context, c *client.Client) error {
+			var err error
+			if depsReverse {
+				deps, err = c.Dependents(ctx, id, depsDepth)
+			} else {
+				deps, err = c.Dependencies(ctx, id, depsDepth)
+			}
+			return err
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Connected dependencies failed:", err)
+			os.Exit(1)
+		}
+
+		if offline {
+			components, err := loadComponents(lodeDir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Offline dependencies failed:", err)
+				os.Exit(1)
+			}
+			deps, err = buildDependencyTree(components, id, depsDepth, depsReverse)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+
+		if depsJSON {
+			data, err := json.MarshalIndent(deps, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Print(renderDependencyTree(deps))
+	},
+}
+
+func init() {
+	addModeFlags(depsCmd, &depsModes)
+	depsCmd.Flags().IntVar(&depsDepth, "depth", 0, "levels to expand (0 = unlimited)")
+	depsCmd.Flags().BoolVar(&depsReverse, "reverse", false, "show dependents instead of dependencies")
+	depsCmd.Flags().BoolVar(&depsJSON, "json", false, "output JSON only")
+}
+
+// buildDependencyTree expands id's depends_on edges (or its dependents when
+// reverse is set) up to depth levels, 0 meaning unlimited. A component that
+// reappears on its own branch is marked as a cycle and not expanded again.
+func buildDependencyTree(components []client.Component, id string, depth int, reverse bool) (*client.Dependencies, error) {
+	edges := map[string][]string{}
+	known := false
+	for _, component := range components {
+		if component.ID == id {
+			known = true
+		}
+		if reverse {
+			for _, dep := range component.DependsOn {
+				edges[dep] = append(edges[dep], component.ID)
+			}
+		} else {
+			edges[component.ID] = component.DependsOn
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("component not found: %s", id)
+	}
+
+	onPath := map[string]bool{id: true}
+	var expand func(from string, level int) []client.DependencyNode
+	expand = func(from string, level int) []client.DependencyNode {
+		if depth > 0 && level > depth {
+			return nil
+		}
+
+		nodes := []client.DependencyNode{}
+		for _, next := range edges[from] {
+			node := client.DependencyNode{ID: next}
+			if onPath[next] {
+				node.Cycle = true
+			} else {
+				onPath[next] = true
+				node.Children = expand(next, level+1)
+				delete(onPath, next)
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+
+	return &client.Dependencies{
+		ID:           id,
+		Depth:        depth,
+		Reverse:      reverse,
+		Dependencies: expand(id, 1),
+	}, nil
+}
+
+func renderDependencyTree(deps *client.Dependencies) string {
+	builder := &strings.Builder{}
+
+	fmt.Fprintln(builder, deps.ID)
+	if len(deps.Dependencies) == 0 {
+		if deps.Reverse {
+			fmt.Fprintln(builder, "  (no dependents)")
+		} else {
+			fmt.Fprintln(builder, "  (no dependencies)")
+		}
+		return builder.String()
+	}
+
+	writeDependencyNodes(builder, deps.Dependencies, "")
+	return builder.String()
+}
+
+func writeDependencyNodes(builder *strings.Builder, nodes []client.DependencyNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		label := node.ID
+		if node.Cycle {
+			label += " (cycle)"
+		}
+		fmt.Fprintf(builder, "%s%s%s\n", prefix, branch, label)
+		writeDependencyNodes(builder, node.Children, prefix+indent)
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/client"
+)
+
+func dependencyFixture() []client.Component {
+	return []client.Component{
+		{ID: "cli", DependsOn: []string{"cli-socket"}},
+		{ID: "cli-socket", DependsOn: []string{"graph-server"}},
+		{ID: "config-loader"},
+		{ID: "graph-server", DependsOn: []string{"config-loader"}},
+	}
+}
+
+func TestBuildDependencyTreeRespectsDepth(t *testing.T) {
+	deps, err := buildDependencyTree(dependencyFixture(), "cli", 2, false)
+	if err != nil {
+		t.Fatalf("buildDependencyTree error: %v", err)
+	}
+
+	output := renderDependencyTree(deps)
+	want := "cli\n└── cli-socket\n    └── graph-server\n"
+	if output != want {
+		t.Fatalf("unexpected tree:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestBuildDependencyTreeReverse(t *testing.T) {
+	deps, err := buildDependencyTree(dependencyFixture(), "config-loader", 0, true)
+	if err != nil {
+		t.Fatalf("buildDependencyTree error: %v", err)
+	}
+
+	output := renderDependencyTree(deps)
+	want := "config-loader\n└── graph-server\n    └── cli-socket\n        └── cli\n"
+	if output != want {
+		t.Fatalf("unexpected tree:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestBuildDependencyTreeMarksCycles(t *testing.T) {
+	components := []client.Component{
+		{ID: "a", DependsOn: []string{"b"}},
+		{ID: "b", DependsOn: []string{"a"}},
+	}
+
+	deps, err := buildDependencyTree(components, "a", 0, false)
+	if err != nil {
+		t.Fatalf("buildDependencyTree error: %v", err)
+	}
+	if !strings.Contains(renderDependencyTree(deps), "a (cycle)") {
+		t.Fatalf("expected cycle marker, got: %s", renderDependencyTree(deps))
+	}
+
+	if _, err := buildDependencyTree(components, "missing", 0, false); err == nil {
+		t.Fatalf("expected error for unknown component")
+	}
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/lodetime/lodetime-cli/validate"
+	"github.com/spf13/cobra"
+)
+
+// Default staleness thresholds, in commits.
+const (
+	defaultDriftWarnAfter  = 5
+	defaultDriftErrorAfter = 15
+)
+
+var (
+	driftWarnAfter  int
+	driftErrorAfter int
+	driftJSON       bool
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift [component]...",
+	Short: "Report components whose YAML has gone stale",
+	Long: `Walks git history and counts, for each component, the commits that touched
+its location since its .lodetime/components/*.yaml last changed. A component
+is a warning after warn_after such commits and an error after error_after:
+
+  drift:
+    warn_after: 5
+    error_after: 15
+
+--warn-after and --error-after override config.yaml. Exits non-zero when any
+component reaches the error threshold.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+
+		thresholds := driftThresholds(project.Config.Drift)
+		if cmd.Flags().Changed("warn-after") {
+			thresholds.WarnAfter = driftWarnAfter
+		}
+		if cmd.Flags().Changed("error-after") {
+			thresholds.ErrorAfter = driftErrorAfter
+		}
+
+		components := project.Components
+		if len(args) > 0 {
+			components = nil
+			for _, id := range args {
+				component, ok := project.Component(id)
+				if !ok {
+					fmt.Fprintf(os.Stderr, "Component not found: %s\n", id)
+					os.Exit(1)
+				}
+				components = append(components, *component)
+			}
+		}
+
+		report, err := buildStalenessReport(filepath.Dir(lodeDir), components, thresholds)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if driftJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			writeStalenessTable(os.Stdout, report)
+		}
+
+		for _, entry := range report.Components {
+			if entry.Severity == string(validate.SeverityError) {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	driftCmd.Flags().IntVar(&driftWarnAfter, "warn-after", defaultDriftWarnAfter, "commits since the YAML changed before warning")
+	driftCmd.Flags().IntVar(&driftErrorAfter, "error-after", defaultDriftErrorAfter, "commits since the YAML changed before erroring")
+	driftCmd.Flags().BoolVar(&driftJSON, "json", false, "output JSON only")
+}
+
+// driftThresholds fills unset thresholds with the defaults.
+func driftThresholds(config model.Drift) model.Drift {
+	if config.WarnAfter <= 0 {
+		config.WarnAfter = defaultDriftWarnAfter
+	}
+	if config.ErrorAfter <= 0 {
+		config.ErrorAfter = defaultDriftErrorAfter
+	}
+	return config
+}
+
+type stalenessReport struct {
+	WarnAfter  int              `json:"warn_after"`
+	ErrorAfter int              `json:"error_after"`
+	Components []stalenessEntry `json:"components"`
+}
+
+type stalenessEntry struct {
+	ID       string `json:"id"`
+	Location string `json:"location,omitempty"`
+	// YAMLCommit and YAMLChanged describe the last commit touching the
+	// component's YAML; both are empty when it was never committed.
+	YAMLCommit  string `json:"yaml_commit,omitempty"`
+	YAMLChanged string `json:"yaml_changed,omitempty"`
+	Commits     int    `json:"commits"`
+	// Severity is ok, warn, error, or skipped for components without a location.
+	Severity string `json:"severity"`
+}
+
+func buildStalenessReport(projectRoot string, components []model.Component, thresholds model.Drift) (stalenessReport, error) {
+	report := stalenessReport{
+		WarnAfter:  thresholds.WarnAfter,
+		ErrorAfter: thresholds.ErrorAfter,
+		Components: []stalenessEntry{},
+	}
+
+	for _, component := range components {
+		entry, err := componentStaleness(projectRoot, component, thresholds)
+		if err != nil {
+			return report, err
+		}
+		report.Components = append(report.Components, entry)
+	}
+	return report, nil
+}
+
+// componentStaleness counts commits touching the component's location after
+// the last commit that touched its YAML. A commit changing both counts as an
+// update, not as drift.
+func componentStaleness(projectRoot string, component model.Component, thresholds model.Drift) (stalenessEntry, error) {
+	entry := stalenessEntry{ID: component.ID, Location: component.Location, Severity: "skipped"}
+	if component.Location == "" {
+		return entry, nil
+	}
+
+	yamlPath := filepath.ToSlash(filepath.Join(".lodetime", component.File))
+	output, err := gitOutput(projectRoot, "log", "-1", "--format=%H %cI", "--", yamlPath)
+	if err != nil {
+		return entry, err
+	}
+
+	rev := "HEAD"
+	if fields := strings.Fields(output); len(fields) == 2 {
+		entry.YAMLCommit, entry.YAMLChanged = fields[0], fields[1]
+		rev = entry.YAMLCommit + "..HEAD"
+	}
+
+	output, err = gitOutput(projectRoot, "rev-list", "--count", rev, "--", component.Location)
+	if err != nil {
+		return entry, err
+	}
+	entry.Commits, err = strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return entry, fmt.Errorf("git rev-list --count: %w", err)
+	}
+
+	switch {
+	case entry.Commits >= thresholds.ErrorAfter:
+		entry.Severity = string(validate.SeverityError)
+	case entry.Commits >= thresholds.WarnAfter:
+		entry.Severity = string(validate.SeverityWarn)
+	default:
+		entry.Severity = "ok"
+	}
+	return entry, nil
+}
+
+func writeStalenessTable(w io.Writer, report stalenessReport) {
+	fmt.Fprintf(w, "YAML staleness (warn after %d, error after %d commits)\n", report.WarnAfter, report.ErrorAfter)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tLOCATION\tCOMMITS\tYAML CHANGED\tSTATUS")
+	for _, entry := range report.Components {
+		changed := "never"
+		if entry.YAMLChanged != "" {
+			changed = entry.YAMLChanged
+			if at, err := time.Parse(time.RFC3339, entry.YAMLChanged); err == nil {
+				changed = at.Format("2006-01-02")
+			}
+			if rev := entry.YAMLCommit; len(rev) > 7 {
+				changed += " " + rev[:7]
+			}
+		}
+		location := entry.Location
+		if location == "" {
+			location = "-"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\n", entry.ID, location, entry.Commits, changed, entry.Severity)
+	}
+	_ = table.Flush()
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/lodetime/lodetime-cli/imports"
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/lodetime/lodetime-cli/validate"
+	"github.com/spf13/cobra"
+)
+
+// Rule IDs for dependency divergence; severities can be overridden under
+// rules: in config.yaml like validation rules.
+const (
+	ruleUndeclaredDependency = "undeclared-dependency"
+	ruleUnusedDependency     = "unused-dependency"
+)
+
+var driftImportsJSON bool
+
+var driftImportsCmd = &cobra.Command{
+	Use:   "imports [component]...",
+	Short: "Compare source imports with depends_on",
+	Long: `Scans the sources under each component's location, maps what they import
+back to the components that own it, and compares the result with depends_on.
+Go files are parsed for imports; Elixir files are scanned, best effort and
+without a BEAM install, for alias, import, use, require, structs and remote
+calls to modules defined in the project.
+
+  undeclared  imports a component not listed in depends_on (error)
+  unused      lists a component in depends_on but never imports it (warn)
+
+Components without sources are skipped. Exits non-zero when an error is found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+		for _, id := range args {
+			if _, ok := project.Component(id); !ok {
+				fmt.Fprintf(os.Stderr, "Component not found: %s\n", id)
+				os.Exit(1)
+			}
+		}
+
+		results, err := scanImports(filepath.Dir(lodeDir), project)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to scan sources:", err)
+			os.Exit(1)
+		}
+
+		report := compareImports(project, results...)
+		if len(args) > 0 {
+			report = filterDivergence(report, args)
+		}
+
+		if driftImportsJSON {
+			data, err := json.MarshalIndent(map[string]any{"components": report}, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			writeDivergence(os.Stdout, report)
+		}
+
+		for _, entry := range report {
+			if validate.Severity(entry.Severity).AtLeast(validate.SeverityError) {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	driftImportsCmd.Flags().BoolVar(&driftImportsJSON, "json", false, "output JSON only")
+	driftCmd.AddCommand(driftImportsCmd)
+}
+
+// scanImports runs every source scanner over the component locations.
+func scanImports(projectRoot string, project *model.Project) ([]*imports.Result, error) {
+	dirs := []string{}
+	for _, component := range project.Components {
+		if component.Location != "" {
+			dirs = append(dirs, component.Location)
+		}
+	}
+
+	results := []*imports.Result{}
+	for _, scan := range []func(string, []string) (*imports.Result, error){imports.Go, imports.Elixir} {
+		result, err := scan(projectRoot, dirs)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// dependencyUse is an imported component and where it is imported.
+type dependencyUse struct {
+	Component string   `json:"component"`
+	Evidence  []string `json:"evidence"`
+}
+
+type importDivergence struct {
+	ID         string          `json:"id"`
+	Files      int             `json:"files"`
+	DependsOn  []string        `json:"depends_on"`
+	Imports    []string        `json:"imports"`
+	Undeclared []dependencyUse `json:"undeclared"`
+	Unused     []string        `json:"unused"`
+	// Severity is the worst problem, ok, or skipped without sources.
+	Severity string `json:"severity"`
+}
+
+// compareImports maps scanned files and imports to components by location and
+// compares the imported components with depends_on.
+func compareImports(project *model.Project, results ...*imports.Result) []importDivergence {
+	components := clientComponents(project.Components)
+
+	files := map[string]int{}
+	uses := map[string]map[string][]string{}
+	for _, result := range results {
+		for _, file := range result.Files {
+			if id, ok := componentForPath(components, file); ok {
+				files[id]++
+			}
+		}
+		for _, imported := range result.Imports {
+			if imported.Path == "" {
+				continue
+			}
+			from, ok := componentForPath(components, imported.File)
+			if !ok {
+				continue
+			}
+			to, ok := componentForPath(components, imported.Path)
+			if !ok || to == from {
+				continue
+			}
+			if uses[from] == nil {
+				uses[from] = map[string][]string{}
+			}
+			evidence := fmt.Sprintf("%s:%d %s", imported.File, imported.Line, imported.Name)
+			uses[from][to] = append(uses[from][to], evidence)
+		}
+	}
+
+	undeclaredSeverity := validate.RuleSeverity(&project.Config, ruleUndeclaredDependency, validate.SeverityError)
+	unusedSeverity := validate.RuleSeverity(&project.Config, ruleUnusedDependency, validate.SeverityWarn)
+
+	report := []importDivergence{}
+	for _, component := range project.Components {
+		entry := importDivergence{
+			ID:         component.ID,
+			Files:      files[component.ID],
+			DependsOn:  uniqueSorted(component.DependsOn),
+			Imports:    []string{},
+			Undeclared: []dependencyUse{},
+			Unused:     []string{},
+			Severity:   "skipped",
+		}
+		if entry.Files == 0 {
+			report = append(report, entry)
+			continue
+		}
+
+		var worst validate.Severity
+		for to, evidence := range uses[component.ID] {
+			entry.Imports = append(entry.Imports, to)
+			if !containsString(entry.DependsOn, to) {
+				sort.Strings(evidence)
+				entry.Undeclared = append(entry.Undeclared, dependencyUse{Component: to, Evidence: evidence})
+				worst = undeclaredSeverity
+			}
+		}
+		sort.Strings(entry.Imports)
+		sort.Slice(entry.Undeclared, func(i, j int) bool {
+			return entry.Undeclared[i].Component < entry.Undeclared[j].Component
+		})
+
+		for _, dep := range entry.DependsOn {
+			if !containsString(entry.Imports, dep) {
+				entry.Unused = append(entry.Unused, dep)
+				if unusedSeverity.Rank() > worst.Rank() {
+					worst = unusedSeverity
+				}
+			}
+		}
+
+		entry.Severity = "ok"
+		if worst != "" {
+			entry.Severity = string(worst)
+		}
+		report = append(report, entry)
+	}
+	return report
+}
+
+func filterDivergence(report []importDivergence, ids []string) []importDivergence {
+	filtered := []importDivergence{}
+	for _, entry := range report {
+		if containsString(ids, entry.ID) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+func writeDivergence(w io.Writer, report []importDivergence) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tFILES\tDEPENDS ON\tIMPORTS\tSTATUS")
+	for _, entry := range report {
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\t%s\n", entry.ID, entry.Files,
+			formatList(entry.DependsOn), formatList(entry.Imports), entry.Severity)
+	}
+	_ = table.Flush()
+
+	problems := []string{}
+	for _, entry := range report {
+		for _, use := range entry.Undeclared {
+			line := fmt.Sprintf("%s imports %s without declaring it (%s", entry.ID, use.Component, use.Evidence[0])
+			if more := len(use.Evidence) - 1; more > 0 {
+				line += fmt.Sprintf(", +%d more", more)
+			}
+			problems = append(problems, line+")")
+		}
+		for _, dep := range entry.Unused {
+			problems = append(problems, fmt.Sprintf("%s declares %s but never imports it", entry.ID, dep))
+		}
+	}
+	if len(problems) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Divergence:")
+	for _, problem := range problems {
+		fmt.Fprintf(w, "  - %s\n", problem)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/imports"
+)
+
+func TestCompareImportsReportsDivergence(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"components/store.yaml": "id: store\nlocation: pkg/store/\ndepends_on: []\n",
+		"components/api.yaml":   "id: api\nlocation: pkg/api/\ndepends_on: [auth]\n",
+		"components/auth.yaml":  "id: auth\nlocation: pkg/auth/\ndepends_on: []\n",
+		"components/docs.yaml":  "id: docs\nlocation: docs/\ndepends_on: [api]\n",
+	})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	result := &imports.Result{
+		Files: []string{"pkg/api/api.go", "pkg/api/routes.go", "pkg/store/store.go", "pkg/auth/auth.go"},
+		Imports: []imports.Import{
+			{File: "pkg/api/api.go", Line: 5, Name: "example.com/pkg/store", Path: "pkg/store"},
+			{File: "pkg/api/routes.go", Line: 3, Name: "example.com/pkg/store", Path: "pkg/store"},
+			{File: "pkg/api/api.go", Line: 4, Name: "fmt"},
+			{File: "pkg/store/store.go", Line: 3, Name: "example.com/pkg/store/internal", Path: "pkg/store/internal"},
+		},
+	}
+
+	report := compareImports(project, result)
+	got := map[string]importDivergence{}
+	for _, entry := range report {
+		got[entry.ID] = entry
+	}
+
+	api := got["api"]
+	if api.Severity != "error" || len(api.Undeclared) != 1 || api.Undeclared[0].Component != "store" {
+		t.Fatalf("expected undeclared store for api, got %+v", api)
+	}
+	if strings.Join(api.Undeclared[0].Evidence, ";") != "pkg/api/api.go:5 example.com/pkg/store;pkg/api/routes.go:3 example.com/pkg/store" {
+		t.Fatalf("unexpected evidence: %v", api.Undeclared[0].Evidence)
+	}
+	if strings.Join(api.Unused, ",") != "auth" {
+		t.Fatalf("expected unused auth, got %v", api.Unused)
+	}
+	if got["store"].Severity != "ok" || got["auth"].Severity != "ok" || got["docs"].Severity != "skipped" {
+		t.Fatalf("unexpected severities: %+v", report)
+	}
+
+	var out bytes.Buffer
+	writeDivergence(&out, report)
+	for _, want := range []string{
+		"api imports store without declaring it (pkg/api/api.go:5 example.com/pkg/store, +1 more)",
+		"api declares auth but never imports it",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
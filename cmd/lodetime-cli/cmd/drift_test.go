@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/model"
+)
+
+// gitCommitFiles writes files under root and commits them.
+func gitCommitFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	writeFiles(t, root, files)
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "change"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", args[0], err, output)
+		}
+	}
+}
+
+func TestComponentStalenessCountsCommitsSinceYAML(t *testing.T) {
+	lodeDir := writeWorkRepo(t)
+	root := filepath.Dir(lodeDir)
+
+	gitCommitFiles(t, root, map[string]string{"lib/config/a.ex": "1"})
+	gitCommitFiles(t, root, map[string]string{".lodetime/components/config-loader.yaml": "id: config-loader\nlocation: lib/config/\ndepends_on: []\n"})
+	gitCommitFiles(t, root, map[string]string{"lib/config/a.ex": "2"})
+	gitCommitFiles(t, root, map[string]string{"lib/config/a.ex": "3", "lib/graph/b.ex": "1"})
+	gitCommitFiles(t, root, map[string]string{"README.md": "unrelated"})
+
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	report, err := buildStalenessReport(root, project.Components, model.Drift{WarnAfter: 2, ErrorAfter: 4})
+	if err != nil {
+		t.Fatalf("buildStalenessReport error: %v", err)
+	}
+
+	got := map[string]stalenessEntry{}
+	for _, entry := range report.Components {
+		got[entry.ID] = entry
+	}
+	if entry := got["config-loader"]; entry.Commits != 2 || entry.Severity != "warn" || entry.YAMLCommit == "" {
+		t.Fatalf("unexpected config-loader entry: %+v", entry)
+	}
+	// graph-server's YAML was committed with the initial fixture, before
+	// any code under lib/graph/ existed.
+	if entry := got["graph-server"]; entry.Commits != 1 || entry.Severity != "ok" {
+		t.Fatalf("unexpected graph-server entry: %+v", entry)
+	}
+
+	var out bytes.Buffer
+	writeStalenessTable(&out, report)
+	if !strings.Contains(out.String(), "warn after 2, error after 4 commits") || !strings.Contains(out.String(), "config-loader") {
+		t.Fatalf("unexpected table:\n%s", out.String())
+	}
+}
+
+func TestDriftThresholdDefaults(t *testing.T) {
+	got := driftThresholds(model.Drift{WarnAfter: 3})
+	if got.WarnAfter != 3 || got.ErrorAfter != defaultDriftErrorAfter {
+		t.Fatalf("unexpected thresholds: %+v", got)
+	}
+}
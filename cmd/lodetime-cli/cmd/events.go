@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	eventsFilters []string
+	eventsJSON    bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream runtime events",
+	Long: `Subscribes to the runtime and prints events as they happen until
+interrupted: file changes seen by the runtime's watcher, and the component
+status changes and logged errors the graph server emits when a .lodetime/
+change makes it reload.
+
+--filter key=value narrows the stream and may be repeated; values may be
+comma-separated. type= is applied by the runtime (one of: ` + strings.Join(client.EventTypes, ", ") + `);
+any other key matches a field of the event data, e.g. --filter component=graph-server.
+
+With --json each event is printed as one JSON line. Requires a socket endpoint;
+the stdio transport cannot stream.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		filter, err := parseEventFilters(eventsFilters)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		endpoint := resolveEndpoint(runtimeEndpoint, lodeDir)
+		if endpoint == client.StdioEndpoint {
+			fmt.Fprintln(os.Stderr, "lode events needs a running runtime on a socket endpoint (see 'lode run')")
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		c, err := dialRuntime(ctx, endpoint, statusTimeout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to connect to runtime:", err)
+			os.Exit(1)
+		}
+		defer c.Close()
+
+		sub, err := c.Subscribe(ctx, filter.types)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Subscribe failed:", err)
+			os.Exit(1)
+		}
+
+		for {
+			event, err := sub.Next(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if errors.Is(err, client.ErrProtocol) {
+					fmt.Fprintln(os.Stderr, "Event stream closed:", err)
+				} else {
+					fmt.Fprintln(os.Stderr, "Event stream failed:", err)
+				}
+				os.Exit(1)
+			}
+			if !filter.matches(event) {
+				continue
+			}
+
+			if eventsJSON {
+				data, err := json.Marshal(event)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+					os.Exit(1)
+				}
+				fmt.Println(string(data))
+				continue
+			}
+			fmt.Println(renderEventHuman(event))
+		}
+	},
+}
+
+func init() {
+	eventsCmd.Flags().StringArrayVar(&eventsFilters, "filter", nil, "only events matching key=value (repeatable)")
+	eventsCmd.Flags().BoolVar(&eventsJSON, "json", false, "output one JSON object per event")
+}
+
+// eventFilter holds parsed --filter flags. Types go to the runtime; data
+// filters are applied here.
+type eventFilter struct {
+	types []string
+	data  map[string][]string
+}
+
+func parseEventFilters(filters []string) (eventFilter, error) {
+	filter := eventFilter{data: map[string][]string{}}
+	for _, raw := range filters {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok || key == "" || value == "" {
+			return filter, fmt.Errorf("invalid --filter %q (expected key=value)", raw)
+		}
+
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if key != "type" {
+				filter.data[key] = append(filter.data[key], item)
+				continue
+			}
+			if !containsString(client.EventTypes, item) {
+				return filter, fmt.Errorf("unknown event type %q (expected one of: %s)", item, strings.Join(client.EventTypes, ", "))
+			}
+			filter.types = append(filter.types, item)
+		}
+	}
+	return filter, nil
+}
+
+// matches applies the type filter again (older runtimes may ignore it) and
+// requires every data key to match one of its values.
+func (f eventFilter) matches(event client.Event) bool {
+	if len(f.types) > 0 && !containsString(f.types, event.Type) {
+		return false
+	}
+	for key, values := range f.data {
+		if !containsString(values, formatValue(event.Data[key])) {
+			return false
+		}
+	}
+	return true
+}
+
+// renderEventHuman formats an event as "15:04:05 type key=value ...".
+func renderEventHuman(event client.Event) string {
+	at := event.At
+	if parsed, err := time.Parse(time.RFC3339Nano, event.At); err == nil {
+		at = parsed.Local().Format("15:04:05")
+	}
+
+	keys := make([]string, 0, len(event.Data))
+	for key := range event.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := []string{at, event.Type}
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", key, formatValue(event.Data[key])))
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
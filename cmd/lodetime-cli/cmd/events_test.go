@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/client"
+)
+
+func TestParseEventFilters(t *testing.T) {
+	filter, err := parseEventFilters([]string{"type=error_logged", "component=graph-server"})
+	if err != nil {
+		t.Fatalf("parseEventFilters error: %v", err)
+	}
+	if strings.Join(filter.types, ",") != "error_logged" {
+		t.Fatalf("unexpected types: %v", filter.types)
+	}
+
+	if !filter.matches(client.Event{Type: "error_logged", Data: map[string]any{"component": "graph-server"}}) {
+		t.Fatalf("expected matching event")
+	}
+	if filter.matches(client.Event{Type: "error_logged", Data: map[string]any{"component": "cli-socket"}}) {
+		t.Fatalf("expected data filter to reject other component")
+	}
+	if filter.matches(client.Event{Type: "component_status_changed", Data: map[string]any{"component": "graph-server"}}) {
+		t.Fatalf("expected type filter to reject component_status_changed")
+	}
+
+	for _, bad := range []string{"type", "type=bogus", "type=checkpoint_started", "=x"} {
+		if _, err := parseEventFilters([]string{bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestRenderEventHuman(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local).Format(time.RFC3339)
+	output := renderEventHuman(client.Event{
+		Type: client.EventComponentStatus,
+		At:   at,
+		Data: map[string]any{"to": "implemented", "component": "graph-server"},
+	})
+	if output != "15:04:05 component_status_changed component=graph-server to=implemented" {
+		t.Fatalf("unexpected output: %q", output)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// gitOutput runs git in dir and returns stdout, folding stderr into errors.
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), message)
+	}
+
+	return stdout.String(), nil
+}
+
+// gitDiffFiles lists files changed between rev and the working tree, relative
+// to dir. rev may come from the command line, so one that looks like an
+// option is rejected rather than passed to git.
+func gitDiffFiles(dir, rev string) ([]string, error) {
+	if strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("invalid git revision %q", rev)
+	}
+	output, err := gitOutput(dir, "diff", "--name-only", "--relative", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+// gitStagedFiles lists files in the index that differ from HEAD, relative to
+// dir.
+func gitStagedFiles(dir string) ([]string, error) {
+	output, err := gitOutput(dir, "diff", "--cached", "--name-only", "--relative")
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+// gitUntrackedFiles lists untracked, non-ignored files relative to dir.
+func gitUntrackedFiles(dir string) ([]string, error) {
+	output, err := gitOutput(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+func splitLines(output string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/graph"
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/lodetime/lodetime-cli/validate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphCyclesJSON bool
+	graphOrderJSON  bool
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Inspect the component dependency graph",
+	Long: `Works on the dependency graph built from depends_on in
+.lodetime/components/*.yaml. No runtime is needed.`,
+}
+
+var graphCyclesCmd = &cobra.Command{
+	Use:   "cycles",
+	Short: "Report circular dependencies",
+	Long: `Prints each dependency cycle as a path, e.g. a → b → c → a. Exits
+non-zero when any cycle is found.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+
+		cycles := graph.New(project.Components).Cycles()
+		if graphCyclesJSON {
+			output, err := renderCyclesJSON(cycles)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Print(renderCyclesHuman(cycles))
+		}
+
+		if len(cycles) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var graphOrderCmd = &cobra.Command{
+	Use:   "order",
+	Short: "Check build_order against depends_on",
+	Long: `Computes a dependency-first order of all components and compares it with
+build_order in config.yaml. Entries listed before one of their dependencies,
+unknown or duplicate entries, and components missing from build_order are
+reported along with a suggested corrected order. Exits non-zero when
+build_order is wrong.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+
+		report := buildOrderReport(project)
+		if graphOrderJSON {
+			output, err := renderOrderJSON(report)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Print(renderOrderHuman(report))
+		}
+
+		if !report.OK {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	graphCyclesCmd.Flags().BoolVar(&graphCyclesJSON, "json", false, "output JSON only")
+	graphOrderCmd.Flags().BoolVar(&graphOrderJSON, "json", false, "output JSON only")
+	graphCmd.AddCommand(graphCyclesCmd)
+	graphCmd.AddCommand(graphOrderCmd)
+}
+
+type orderReport struct {
+	OK         bool               `json:"ok"`
+	BuildOrder []string           `json:"build_order"`
+	Suggested  []string           `json:"suggested"`
+	Findings   []validate.Finding `json:"findings"`
+}
+
+// buildOrderReport runs the build-order rule from lode validate and pairs
+// its findings with a suggested order that keeps build_order where it can.
+func buildOrderReport(project *model.Project) orderReport {
+	report := orderReport{
+		OK:         true,
+		BuildOrder: project.Config.BuildOrder,
+		Suggested:  graph.New(project.Components).Order(project.Config.BuildOrder),
+		Findings:   []validate.Finding{},
+	}
+	if report.BuildOrder == nil {
+		report.BuildOrder = []string{}
+	}
+
+	for _, finding := range validate.Project(project).Findings {
+		if finding.Rule != validate.RuleBuildOrder {
+			continue
+		}
+		report.Findings = append(report.Findings, finding)
+		if finding.Severity == validate.SeverityError {
+			report.OK = false
+		}
+	}
+
+	return report
+}
+
+func renderOrderJSON(report orderReport) (string, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func renderOrderHuman(report orderReport) string {
+	builder := &strings.Builder{}
+
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(builder, "build_order is consistent with depends_on")
+		return builder.String()
+	}
+
+	fmt.Fprintln(builder, "build_order problems:")
+	for _, finding := range report.Findings {
+		fmt.Fprintf(builder, "  - %s: %s\n", finding.Severity, finding.Message)
+	}
+
+	fmt.Fprintln(builder)
+	fmt.Fprintln(builder, "Suggested build_order:")
+	for _, id := range report.Suggested {
+		fmt.Fprintf(builder, "  - %s\n", id)
+	}
+
+	return builder.String()
+}
+
+func renderCyclesJSON(cycles []graph.Cycle) (string, error) {
+	data, err := json.MarshalIndent(map[string]any{"cycles": cycles}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func renderCyclesHuman(cycles []graph.Cycle) string {
+	if len(cycles) == 0 {
+		return "No circular dependencies\n"
+	}
+
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "Found %d dependency cycle(s):\n", len(cycles))
+	for _, cycle := range cycles {
+		fmt.Fprintf(builder, "  %s\n", cycle)
+	}
+	return builder.String()
+}
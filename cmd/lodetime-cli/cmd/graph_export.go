@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/graph"
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphExportFormat string
+	graphExportZone   string
+	graphExportRoot   string
+)
+
+var graphExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the architecture graph",
+	Long: `Writes components (styled by status) and contracts as nodes, with
+depends_on and implements_contracts as edges, in DOT, Mermaid, JSON or
+GraphML.
+
+--zone keeps components located in a zone. --root keeps a component and
+everything it depends on. A filtered export keeps the contracts a kept
+component implements; an unfiltered one keeps every contract.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		render, ok := exportRenderers[graphExportFormat]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown format %q (expected dot, mermaid, json or graphml)\n", graphExportFormat)
+			os.Exit(1)
+		}
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+
+		exported, err := buildExportGraph(project, graphExportZone, graphExportRoot)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		output, err := render(exported)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to render %s: %v\n", graphExportFormat, err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+	},
+}
+
+func init() {
+	graphExportCmd.Flags().StringVar(&graphExportFormat, "format", "dot", "output format (dot|mermaid|json|graphml)")
+	graphExportCmd.Flags().StringVar(&graphExportZone, "zone", "", "only components in this zone")
+	graphExportCmd.Flags().StringVar(&graphExportRoot, "root", "", "only this component and its dependencies")
+	graphCmd.AddCommand(graphExportCmd)
+}
+
+const (
+	nodeComponent = "component"
+	nodeContract  = "contract"
+
+	edgeDependsOn  = "depends_on"
+	edgeImplements = "implements"
+)
+
+type exportNode struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+}
+
+// key is unique across node kinds; a contract may share an ID with a
+// component.
+func (n exportNode) key() string {
+	return n.Kind + ":" + n.ID
+}
+
+type exportEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// toKind is the kind of node an edge points at. Edges always start at a
+// component.
+func (e exportEdge) toKind() string {
+	if e.Kind == edgeImplements {
+		return nodeContract
+	}
+	return nodeComponent
+}
+
+type exportGraph struct {
+	Nodes []exportNode `json:"nodes"`
+	Edges []exportEdge `json:"edges"`
+}
+
+// buildExportGraph selects components by zone and root, then adds the edges
+// between them and the contracts they implement. Without filters every
+// contract is exported, implemented or not.
+func buildExportGraph(project *model.Project, zone, root string) (*exportGraph, error) {
+	if zone != "" {
+		if _, ok := project.Config.Zones[zone]; !ok {
+			return nil, fmt.Errorf("unknown zone: %s", zone)
+		}
+	}
+
+	keep := map[string]bool{}
+	for _, component := range project.Components {
+		keep[component.ID] = true
+	}
+	if root != "" {
+		if _, ok := project.Component(root); !ok {
+			return nil, fmt.Errorf("component not found: %s", root)
+		}
+		keep = map[string]bool{}
+		for _, id := range graph.New(project.Components).Closure(root) {
+			keep[id] = true
+		}
+	}
+
+	exported := &exportGraph{Nodes: []exportNode{}, Edges: []exportEdge{}}
+	contracts := map[string]bool{}
+	for _, component := range project.Components {
+		componentZone := zoneForPath(project.Config.Zones, component.Location)
+		if !keep[component.ID] || (zone != "" && componentZone != zone) {
+			delete(keep, component.ID)
+			continue
+		}
+		exported.Nodes = append(exported.Nodes, exportNode{
+			ID:     component.ID,
+			Kind:   nodeComponent,
+			Name:   component.Name,
+			Status: component.Status,
+			Zone:   componentZone,
+		})
+	}
+
+	for _, component := range project.Components {
+		if !keep[component.ID] {
+			continue
+		}
+		for _, dep := range component.DependsOn {
+			if keep[dep] {
+				exported.Edges = append(exported.Edges, exportEdge{From: component.ID, To: dep, Kind: edgeDependsOn})
+			}
+		}
+		for _, id := range component.ImplementsContracts {
+			if _, ok := project.Contract(id); ok {
+				contracts[id] = true
+				exported.Edges = append(exported.Edges, exportEdge{From: component.ID, To: id, Kind: edgeImplements})
+			}
+		}
+	}
+
+	filtered := zone != "" || root != ""
+	for _, contract := range project.Contracts {
+		if contracts[contract.ID] || !filtered {
+			exported.Nodes = append(exported.Nodes, exportNode{ID: contract.ID, Kind: nodeContract, Name: contract.Name})
+		}
+	}
+
+	sort.SliceStable(exported.Edges, func(i, j int) bool {
+		a, b := exported.Edges[i], exported.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.To < b.To
+	})
+
+	return exported, nil
+}
+
+var exportRenderers = map[string]func(*exportGraph) (string, error){
+	"dot":     renderExportDOT,
+	"mermaid": renderExportMermaid,
+	"json":    renderExportJSON,
+	"graphml": renderExportGraphML,
+}
+
+// statusStyles holds fill and border colours per component status, shared by
+// the DOT and Mermaid renderers.
+var statusStyles = map[string]struct{ fill, stroke string }{
+	model.StatusPlanned:      {"#eeeeee", "#9e9e9e"},
+	model.StatusImplementing: {"#fff9c4", "#f9a825"},
+	model.StatusImplemented:  {"#c8e6c9", "#2e7d32"},
+	model.StatusDeprecated:   {"#ffcdd2", "#c62828"},
+}
+
+func renderExportJSON(exported *exportGraph) (string, error) {
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+func renderExportDOT(exported *exportGraph) (string, error) {
+	builder := &strings.Builder{}
+	fmt.Fprintln(builder, "digraph lodetime {")
+	fmt.Fprintln(builder, "  rankdir=LR;")
+	fmt.Fprintln(builder, `  node [fontname="Helvetica", shape=box, style="rounded,filled"];`)
+
+	for _, node := range exported.Nodes {
+		if node.Kind == nodeContract {
+			fmt.Fprintf(builder, "  %q [label=%q, shape=note, style=filled, fillcolor=\"#e3f2fd\"];\n", node.key(), node.ID)
+			continue
+		}
+		style, ok := statusStyles[node.Status]
+		attrs := fmt.Sprintf("label=%q", node.ID)
+		if ok {
+			attrs += fmt.Sprintf(", fillcolor=%q, color=%q", style.fill, style.stroke)
+		}
+		if node.Status == model.StatusPlanned {
+			attrs += `, style="rounded,filled,dashed"`
+		}
+		fmt.Fprintf(builder, "  %q [%s];\n", node.key(), attrs)
+	}
+
+	for _, edge := range exported.Edges {
+		from := exportNode{ID: edge.From, Kind: nodeComponent}.key()
+		to := exportNode{ID: edge.To, Kind: edge.toKind()}.key()
+		if edge.Kind == edgeImplements {
+			fmt.Fprintf(builder, "  %q -> %q [style=dashed, label=\"implements\"];\n", from, to)
+			continue
+		}
+		fmt.Fprintf(builder, "  %q -> %q;\n", from, to)
+	}
+
+	fmt.Fprintln(builder, "}")
+	return builder.String(), nil
+}
+
+// mermaidID turns a node key into a Mermaid-safe identifier. Every byte
+// other than a letter or digit, '_' included, becomes _ and two hex digits,
+// so distinct IDs such as a-b and a_b never share a node.
+func mermaidID(kind, id string) string {
+	builder := &strings.Builder{}
+	builder.WriteString(kind + "_")
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+			builder.WriteByte(c)
+		} else {
+			fmt.Fprintf(builder, "_%02x", c)
+		}
+	}
+	return builder.String()
+}
+
+// mermaidLabel quotes a node label. Mermaid does not understand backslash
+// escapes, so quotes become its #quot; entity.
+func mermaidLabel(label string) string {
+	return `"` + strings.ReplaceAll(label, `"`, "#quot;") + `"`
+}
+
+func renderExportMermaid(exported *exportGraph) (string, error) {
+	builder := &strings.Builder{}
+	fmt.Fprintln(builder, "flowchart LR")
+
+	byStatus := map[string][]string{}
+	for _, node := range exported.Nodes {
+		id := mermaidID(node.Kind, node.ID)
+		if node.Kind == nodeContract {
+			fmt.Fprintf(builder, "  %s{{%s}}\n", id, mermaidLabel(node.ID))
+			continue
+		}
+		fmt.Fprintf(builder, "  %s[%s]\n", id, mermaidLabel(node.ID))
+		if node.Status != "" {
+			byStatus[node.Status] = append(byStatus[node.Status], id)
+		}
+	}
+
+	for _, edge := range exported.Edges {
+		from := mermaidID(nodeComponent, edge.From)
+		to := mermaidID(edge.toKind(), edge.To)
+		if edge.Kind == edgeImplements {
+			fmt.Fprintf(builder, "  %s -. implements .-> %s\n", from, to)
+			continue
+		}
+		fmt.Fprintf(builder, "  %s --> %s\n", from, to)
+	}
+
+	for _, status := range model.Statuses {
+		if len(byStatus[status]) == 0 {
+			continue
+		}
+		style := statusStyles[status]
+		fmt.Fprintf(builder, "  classDef %s fill:%s,stroke:%s\n", status, style.fill, style.stroke)
+		fmt.Fprintf(builder, "  class %s %s\n", strings.Join(byStatus[status], ","), status)
+	}
+
+	return builder.String(), nil
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+func renderExportGraphML(exported *exportGraph) (string, error) {
+	doc := graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "kind", For: "node", Name: "kind", Type: "string"},
+			{ID: "name", For: "node", Name: "name", Type: "string"},
+			{ID: "status", For: "node", Name: "status", Type: "string"},
+			{ID: "zone", For: "node", Name: "zone", Type: "string"},
+			{ID: "edge_kind", For: "edge", Name: "kind", Type: "string"},
+		},
+	}
+	doc.Graph.ID = "lodetime"
+	doc.Graph.EdgeDefault = "directed"
+
+	for _, node := range exported.Nodes {
+		data := []graphMLData{{Key: "kind", Value: node.Kind}}
+		for _, field := range []graphMLData{{"name", node.Name}, {"status", node.Status}, {"zone", node.Zone}} {
+			if field.Value != "" {
+				data = append(data, field)
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.key(), Data: data})
+	}
+	for _, edge := range exported.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: exportNode{ID: edge.From, Kind: nodeComponent}.key(),
+			Target: exportNode{ID: edge.To, Kind: edge.toKind()}.key(),
+			Data:   []graphMLData{{Key: "edge_kind", Value: edge.Kind}},
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
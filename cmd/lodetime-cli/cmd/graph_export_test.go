@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func exportFixture(t *testing.T, zone string) *exportGraph {
+	t.Helper()
+
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"config.yaml":                   "project: fixture\nschema_version: 1\nzones:\n  core:\n    paths: [lib/core/]\n  app:\n    paths: [lib/app/]\n",
+		"components/config-loader.yaml": "id: config-loader\nstatus: implemented\nlocation: lib/core/config/\ndepends_on: []\n",
+		"components/graph-server.yaml":  "id: graph-server\nstatus: implementing\nlocation: lib/core/graph/\ndepends_on: [config-loader]\nimplements_contracts: [graph-api]\n",
+		"components/cli.yaml":           "id: cli\nstatus: planned\nlocation: lib/app/cli/\ndepends_on: [graph-server]\n",
+		"contracts/graph-api.yaml":      "id: graph-api\nname: Graph API\n",
+		"contracts/mcp-tools.yaml":      "id: mcp-tools\nname: MCP Tools\n",
+	})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	exported, err := buildExportGraph(project, zone, "")
+	if err != nil {
+		t.Fatalf("buildExportGraph error: %v", err)
+	}
+	return exported
+}
+
+func TestBuildExportGraphFiltersByZone(t *testing.T) {
+	exported := exportFixture(t, "core")
+
+	nodes := []string{}
+	for _, node := range exported.Nodes {
+		nodes = append(nodes, node.key())
+	}
+	if got := strings.Join(nodes, ","); got != "component:config-loader,component:graph-server,contract:graph-api" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+	if len(exported.Edges) != 2 {
+		t.Fatalf("expected depends_on and implements edges, got %+v", exported.Edges)
+	}
+}
+
+func TestBuildExportGraphUnfilteredKeepsEveryContract(t *testing.T) {
+	exported := exportFixture(t, "")
+
+	nodes := []string{}
+	for _, node := range exported.Nodes {
+		nodes = append(nodes, node.key())
+	}
+	want := "component:cli,component:config-loader,component:graph-server,contract:graph-api,contract:mcp-tools"
+	if got := strings.Join(nodes, ","); got != want {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+}
+
+func TestRenderExportFormats(t *testing.T) {
+	exported := exportFixture(t, "core")
+
+	dot, _ := renderExportDOT(exported)
+	for _, want := range []string{
+		`"component:graph-server" -> "component:config-loader";`,
+		`"component:graph-server" -> "contract:graph-api" [style=dashed, label="implements"];`,
+		`"component:config-loader" [label="config-loader", fillcolor="#c8e6c9"`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("expected %q in DOT output, got: %s", want, dot)
+		}
+	}
+
+	mermaid, _ := renderExportMermaid(exported)
+	for _, want := range []string{
+		"flowchart LR\n",
+		`component_graph_2dserver["graph-server"]`,
+		`contract_graph_2dapi{{"graph-api"}}`,
+		"component_graph_2dserver --> component_config_2dloader",
+		"component_graph_2dserver -. implements .-> contract_graph_2dapi",
+		"class component_graph_2dserver implementing",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Fatalf("expected %q in Mermaid output, got: %s", want, mermaid)
+		}
+	}
+
+	graphml, err := renderExportGraphML(exported)
+	if err != nil {
+		t.Fatalf("renderExportGraphML error: %v", err)
+	}
+	var doc graphML
+	if err := xml.Unmarshal([]byte(graphml), &doc); err != nil {
+		t.Fatalf("invalid GraphML: %v", err)
+	}
+	if len(doc.Graph.Nodes) != 3 || len(doc.Graph.Edges) != 2 {
+		t.Fatalf("unexpected GraphML graph: %+v", doc.Graph)
+	}
+}
+
+func TestMermaidIDKeepsIDsDistinct(t *testing.T) {
+	if a, b := mermaidID(nodeComponent, "a-b"), mermaidID(nodeComponent, "a_b"); a == b {
+		t.Fatalf("a-b and a_b share the Mermaid ID %s", a)
+	}
+	if got := mermaidID(nodeContract, "graph.api_v1"); got != "contract_graph_2eapi_5fv1" {
+		t.Fatalf("unexpected ID: %s", got)
+	}
+}
+
+func TestMermaidLabelUsesEntities(t *testing.T) {
+	if got := mermaidLabel(`say "hi"`); got != `"say #quot;hi#quot;"` {
+		t.Fatalf("unexpected label: %s", got)
+	}
+}
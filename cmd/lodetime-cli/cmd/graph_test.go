@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/graph"
+)
+
+func TestRenderCyclesHuman(t *testing.T) {
+	if output := renderCyclesHuman(nil); output != "No circular dependencies\n" {
+		t.Fatalf("unexpected output: %q", output)
+	}
+
+	output := renderCyclesHuman([]graph.Cycle{{"a", "b", "a"}})
+	if !strings.Contains(output, "Found 1 dependency cycle(s)") || !strings.Contains(output, "  a → b → a\n") {
+		t.Fatalf("unexpected output: %s", output)
+	}
+}
+
+func TestBuildOrderReportSuggestsOrder(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"config.yaml":                   "project: fixture\nschema_version: 1\nbuild_order: [graph-server, config-loader]\n",
+		"components/config-loader.yaml": "id: config-loader\ndepends_on: []\n",
+		"components/graph-server.yaml":  "id: graph-server\ndepends_on: [config-loader]\n",
+		"components/cli.yaml":           "id: cli\ndepends_on: []\n",
+	})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	report := buildOrderReport(project)
+	if report.OK {
+		t.Fatalf("expected build_order to fail")
+	}
+	if got := strings.Join(report.Suggested, ","); got != "config-loader,graph-server,cli" {
+		t.Fatalf("unexpected suggested order: %s", got)
+	}
+
+	output := renderOrderHuman(report)
+	for _, want := range []string{
+		"error: build_order lists `graph-server` before its dependency `config-loader`",
+		"warn: component `cli` is missing from build_order",
+		"Suggested build_order:\n  - config-loader\n  - graph-server\n  - cli\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got: %s", want, output)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies hook scripts written by lode hooks install.
+const hookMarker = "# lode-hooks: managed"
+
+// chainedSuffix is appended to a pre-existing hook that lode now runs first.
+const chainedSuffix = ".lode-chained"
+
+// gitHooks maps each hook lode manages to the check it runs.
+var gitHooks = []struct {
+	name    string
+	command string
+}{
+	{"pre-commit", "lode check --staged"},
+	{"pre-push", "lode check"},
+}
+
+// Hook states reported by lode hooks status.
+const (
+	hookMissing   = "not installed"
+	hookInstalled = "installed"
+	hookChained   = "installed, chains existing hook"
+	hookForeign   = "not installed, existing hook"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks that run lode check",
+	Long: `Installs git hooks that run lode check: pre-commit runs 'lode check --staged'
+and pre-push runs 'lode check'. The hooks validate offline when no runtime is
+reachable.
+
+Which hooks are installed follows triggers.git in .lodetime/config.yaml:
+
+  triggers:
+    git:
+      pre_commit_hook: true
+      pre_push_hook: true
+
+An existing hook is kept and run first; uninstall restores it.`,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the hooks enabled in config.yaml",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir, dir := requireHooksDir()
+
+		project, err := loadProject(lodeDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+			os.Exit(1)
+		}
+
+		enabled := enabledHooks(project.Config.Triggers.Git)
+		if len(enabled) == 0 {
+			fmt.Fprintln(os.Stderr, "No hooks enabled; set triggers.git.pre_commit_hook or pre_push_hook to true in .lodetime/config.yaml")
+			os.Exit(1)
+		}
+
+		prefix, err := gitOutput(filepath.Dir(lodeDir), "rev-parse", "--show-prefix")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		for _, name := range enabled {
+			state, err := installHook(dir, name, strings.TrimSpace(prefix))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to install %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			fmt.Printf("%s: %s\n", name, state)
+		}
+	},
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove lode's hooks and restore chained ones",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, dir := requireHooksDir()
+
+		for _, hook := range gitHooks {
+			removed, err := uninstallHook(dir, hook.name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to uninstall %s: %v\n", hook.name, err)
+				os.Exit(1)
+			}
+			if removed {
+				fmt.Printf("%s: removed\n", hook.name)
+			}
+		}
+	},
+}
+
+var hooksStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which hooks are installed",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir, dir := requireHooksDir()
+
+		var trigger model.GitTrigger
+		if project, err := loadProject(lodeDir); err == nil {
+			trigger = project.Config.Triggers.Git
+		}
+
+		fmt.Print(renderHooksStatus(dir, trigger))
+	},
+}
+
+func init() {
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	hooksCmd.AddCommand(hooksStatusCmd)
+}
+
+// requireHooksDir returns the .lodetime path and the git hooks directory,
+// exiting when either is missing.
+func requireHooksDir() (string, string) {
+	lodeDir := findLodeTimeRoot()
+	if lodeDir == "" {
+		fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+		os.Exit(1)
+	}
+
+	dir, err := gitHooksDir(filepath.Dir(lodeDir))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Not a git repository:", err)
+		os.Exit(1)
+	}
+	return lodeDir, dir
+}
+
+// gitHooksDir resolves the hooks directory, honouring core.hooksPath.
+func gitHooksDir(projectRoot string) (string, error) {
+	output, err := gitOutput(projectRoot, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(output)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectRoot, dir)
+	}
+	return dir, nil
+}
+
+func enabledHooks(trigger model.GitTrigger) []string {
+	enabled := []string{}
+	if trigger.PreCommitHook {
+		enabled = append(enabled, "pre-commit")
+	}
+	if trigger.PrePushHook {
+		enabled = append(enabled, "pre-push")
+	}
+	return enabled
+}
+
+func hookCommand(name string) string {
+	for _, hook := range gitHooks {
+		if hook.name == name {
+			return hook.command
+		}
+	}
+	return ""
+}
+
+// hookScript runs a chained hook first, then lode. Git runs hooks from the
+// top of the worktree, so lode is run from the project root at prefix (as
+// printed by git rev-parse --show-prefix) to find .lodetime/. A missing lode
+// binary skips the check rather than blocking every commit.
+func hookScript(name, prefix string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+# Installed by 'lode hooks install'; remove with 'lode hooks uninstall'.
+chained="$(dirname "$0")/%s%s"
+if [ -x "$chained" ]; then
+	"$chained" "$@" || exit $?
+fi
+if ! command -v lode >/dev/null 2>&1; then
+	echo "lode not found on PATH; skipping LodeTime %s check" >&2
+	exit 0
+fi
+cd "$(git rev-parse --show-toplevel)"/%s || exit 1
+exec %s
+`, hookMarker, name, chainedSuffix, name, shellQuote(prefix), hookCommand(name))
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func isManagedHook(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), hookMarker)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// hookState reports whether lode's hook is installed in dir.
+func hookState(dir, name string) string {
+	path := filepath.Join(dir, name)
+	switch {
+	case !fileExists(path):
+		return hookMissing
+	case !isManagedHook(path):
+		return hookForeign
+	case fileExists(path + chainedSuffix):
+		return hookChained
+	}
+	return hookInstalled
+}
+
+// installHook writes lode's hook for the project at prefix in the worktree.
+// An existing hook that lode did not write is moved aside and chained;
+// reinstalling replaces lode's own script.
+func installHook(dir, name, prefix string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name)
+	if fileExists(path) && !isManagedHook(path) {
+		if fileExists(path + chainedSuffix) {
+			return "", fmt.Errorf("%s already exists", path+chainedSuffix)
+		}
+		if err := os.Rename(path, path+chainedSuffix); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(hookScript(name, prefix)), 0o755); err != nil {
+		return "", err
+	}
+	return hookState(dir, name), nil
+}
+
+// uninstallHook removes lode's hook and restores a chained one. It reports
+// whether anything was removed.
+func uninstallHook(dir, name string) (bool, error) {
+	path := filepath.Join(dir, name)
+	if !isManagedHook(path) {
+		return false, nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		return false, err
+	}
+	if err := os.Rename(path+chainedSuffix, path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return true, err
+	}
+	return true, nil
+}
+
+func renderHooksStatus(dir string, trigger model.GitTrigger) string {
+	builder := &strings.Builder{}
+
+	enabled := enabledHooks(trigger)
+	fmt.Fprintf(builder, "Hooks directory: %s\n", dir)
+	for _, hook := range gitHooks {
+		setting := "disabled"
+		if containsString(enabled, hook.name) {
+			setting = "enabled"
+		}
+		fmt.Fprintf(builder, "  %-11s %s; %s in config.yaml; runs '%s'\n", hook.name, hookState(dir, hook.name), setting, hook.command)
+	}
+
+	return builder.String()
+}
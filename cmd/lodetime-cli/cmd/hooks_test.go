@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/model"
+)
+
+func TestInstallHookChainsExistingHook(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	existing := "#!/bin/sh\necho existing\n"
+	if err := os.WriteFile(filepath.Join(dir, "pre-commit"), []byte(existing), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if state := hookState(dir, "pre-commit"); state != hookForeign {
+		t.Fatalf("expected foreign hook, got %q", state)
+	}
+
+	state, err := installHook(dir, "pre-commit", "")
+	if err != nil {
+		t.Fatalf("installHook error: %v", err)
+	}
+	if state != hookChained {
+		t.Fatalf("expected chained hook, got %q", state)
+	}
+
+	script, err := os.ReadFile(filepath.Join(dir, "pre-commit"))
+	if err != nil {
+		t.Fatalf("read hook: %v", err)
+	}
+	for _, want := range []string{hookMarker, "pre-commit.lode-chained", "exec lode check --staged"} {
+		if !strings.Contains(string(script), want) {
+			t.Fatalf("expected %q in hook script:\n%s", want, script)
+		}
+	}
+
+	// Reinstalling must not chain lode's own script.
+	if _, err := installHook(dir, "pre-commit", ""); err != nil {
+		t.Fatalf("reinstall error: %v", err)
+	}
+	chained, err := os.ReadFile(filepath.Join(dir, "pre-commit.lode-chained"))
+	if err != nil || string(chained) != existing {
+		t.Fatalf("expected chained hook preserved, got %q (%v)", chained, err)
+	}
+
+	removed, err := uninstallHook(dir, "pre-commit")
+	if err != nil || !removed {
+		t.Fatalf("uninstallHook = %v, %v", removed, err)
+	}
+	restored, err := os.ReadFile(filepath.Join(dir, "pre-commit"))
+	if err != nil || string(restored) != existing {
+		t.Fatalf("expected original hook restored, got %q (%v)", restored, err)
+	}
+	if removed, _ := uninstallHook(dir, "pre-commit"); removed {
+		t.Fatalf("expected foreign hook to be left alone")
+	}
+}
+
+func TestHookRunsFromProjectSubdirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	if output, err := exec.Command("git", "-C", root, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+	projectRoot := filepath.Join(root, "app")
+	bin := filepath.Join(t.TempDir(), "bin")
+	writeFiles(t, projectRoot, map[string]string{".lodetime/config.yaml": "project: fixture\n"})
+	writeFiles(t, bin, map[string]string{"lode": "#!/bin/sh\necho \"$(pwd) $*\"\n"})
+	if err := os.Chmod(filepath.Join(bin, "lode"), 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	prefix, err := gitOutput(projectRoot, "rev-parse", "--show-prefix")
+	if err != nil {
+		t.Fatalf("show-prefix: %v", err)
+	}
+	hooks, err := gitHooksDir(projectRoot)
+	if err != nil {
+		t.Fatalf("gitHooksDir: %v", err)
+	}
+	if _, err := installHook(hooks, "pre-commit", strings.TrimSpace(prefix)); err != nil {
+		t.Fatalf("installHook error: %v", err)
+	}
+
+	// Git runs hooks from the top of the worktree.
+	hook := exec.Command(filepath.Join(hooks, "pre-commit"))
+	hook.Dir = root
+	hook.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	output, err := hook.CombinedOutput()
+	if err != nil {
+		t.Fatalf("hook failed: %v\n%s", err, output)
+	}
+	if got, want := strings.TrimSpace(string(output)), projectRoot+" check --staged"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderHooksStatus(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := installHook(dir, "pre-push", ""); err != nil {
+		t.Fatalf("installHook error: %v", err)
+	}
+
+	output := renderHooksStatus(dir, model.GitTrigger{PrePushHook: true})
+	for _, want := range []string{
+		"pre-commit  not installed; disabled in config.yaml; runs 'lode check --staged'",
+		"pre-push    installed; enabled in config.yaml; runs 'lode check'",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
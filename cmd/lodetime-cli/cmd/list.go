@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listModes    modeFlags
+	listStatus   string
+	listZone     string
+	listLanguage string
+	listFormat   string
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List components",
+	Long: `Lists components with optional status, zone and language filters.
+
+A component's zone is the zone whose paths contain its location. Components
+without a language field are classified by the source files under their
+location.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		mode, err := listModes.resolve()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if listStatus != "" && !containsString(model.Statuses, listStatus) {
+			fmt.Fprintf(os.Stderr, "Unknown status %q (expected one of: %s)\n", listStatus, strings.Join(model.Statuses, ", "))
+			os.Exit(1)
+		}
+		switch listFormat {
+		case "table", "json", "csv":
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format %q (expected table, json or csv)\n", listFormat)
+			os.Exit(1)
+		}
+
+		// Zones come from config.yaml alone; component specs are only loaded
+		// offline, so a broken one does not stop the runtime from answering.
+		config, err := model.ReadConfig(filepath.Join(lodeDir, "config.yaml"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .lodetime/config.yaml:", err)
+			os.Exit(1)
+		}
+		zones := config.Zones
+		if listZone != "" {
+			if _, ok := zones[listZone]; !ok {
+				fmt.Fprintf(os.Stderr, "Unknown zone %q\n", listZone)
+				os.Exit(1)
+			}
+		}
+
+		var components []client.Component
+		offline, err := queryRuntime(lodeDir, mode, "list", func(ctx context.Context, c *client.Client) error {
+			var err error
+			components, err = c.List(ctx, listStatus)
+			return err
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Connected list failed:", err)
+			os.Exit(1)
+		}
+
+		if offline {
+			project, err := loadProject(lodeDir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to load .lodetime/:", err)
+				os.Exit(1)
+			}
+			components = clientComponents(project.Components)
+		}
+
+		rows := filterComponents(components, zones, filepath.Dir(lodeDir), listFilter{
+			status:   listStatus,
+			zone:     listZone,
+			language: strings.ToLower(listLanguage),
+		})
+
+		switch listFormat {
+		case "json":
+			output, err := renderListJSON(rows, offline)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		case "csv":
+			if err := writeListCSV(os.Stdout, rows); err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render CSV:", err)
+				os.Exit(1)
+			}
+		default:
+			writeListTable(os.Stdout, rows)
+		}
+	},
+}
+
+func init() {
+	addModeFlags(listCmd, &listModes)
+	listCmd.Flags().StringVar(&listStatus, "status", "", "filter by status (planned|implementing|implemented|deprecated)")
+	listCmd.Flags().StringVar(&listZone, "zone", "", "filter by zone from config.yaml")
+	listCmd.Flags().StringVar(&listLanguage, "language", "", "filter by language")
+	listCmd.Flags().StringVar(&listFormat, "format", "table", "output format (table|json|csv)")
+}
+
+type listFilter struct {
+	status   string
+	zone     string
+	language string
+}
+
+type listRow struct {
+	client.Component
+	Zone string `json:"zone,omitempty"`
+}
+
+// filterComponents annotates components with zone and language and keeps
+// those matching filter. The status filter is reapplied so runtimes that
+// ignore it still produce the right rows.
+func filterComponents(components []client.Component, zones map[string]model.Zone, projectRoot string, filter listFilter) []listRow {
+	rows := []listRow{}
+	for _, component := range components {
+		if filter.status != "" && component.Status != filter.status {
+			continue
+		}
+
+		row := listRow{Component: component, Zone: zoneForPath(zones, component.Location)}
+		if filter.zone != "" && row.Zone != filter.zone {
+			continue
+		}
+
+		row.Language = componentLanguage(projectRoot, component)
+		if filter.language != "" && strings.ToLower(row.Language) != filter.language {
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].ID < rows[j].ID
+	})
+	return rows
+}
+
+// zoneForPath returns the zone with the most specific path containing path.
+func zoneForPath(zones map[string]model.Zone, path string) string {
+	path = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(path), "./"), "/")
+
+	best, bestLen := "", -1
+	for name, zone := range zones {
+		for _, zonePath := range zone.Paths {
+			zonePath = strings.TrimSuffix(strings.TrimPrefix(zonePath, "./"), "/")
+			if path != zonePath && !strings.HasPrefix(path, zonePath+"/") {
+				continue
+			}
+			if len(zonePath) > bestLen || (len(zonePath) == bestLen && name < best) {
+				best, bestLen = name, len(zonePath)
+			}
+		}
+	}
+
+	return best
+}
+
+var languageExtensions = map[string]string{
+	".ex":  "elixir",
+	".exs": "elixir",
+	".go":  "go",
+	".py":  "python",
+	".rs":  "rust",
+	".ts":  "typescript",
+	".js":  "javascript",
+}
+
+// componentLanguage returns the language the runtime or spec gives, and
+// detects one from the component's location only when that is empty. lode
+// list and lode component share it so both show the same language.
+func componentLanguage(projectRoot string, component client.Component) string {
+	if component.Language != "" || component.Location == "" {
+		return component.Language
+	}
+	return detectLanguage(filepath.Join(projectRoot, filepath.FromSlash(component.Location)))
+}
+
+// detectLanguage returns the language with the most source files under dir,
+// or "" if none are recognised (for example, a planned component).
+func detectLanguage(dir string) string {
+	counts := map[string]int{}
+	_ = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		if language, ok := languageExtensions[filepath.Ext(path)]; ok {
+			counts[language]++
+		}
+		return nil
+	})
+
+	best, bestCount := "", 0
+	for language, count := range counts {
+		if count > bestCount || (count == bestCount && language < best) {
+			best, bestCount = language, count
+		}
+	}
+	return best
+}
+
+func renderListJSON(rows []listRow, offline bool) (string, error) {
+	view := struct {
+		Mode       string    `json:"mode"`
+		Components []listRow `json:"components"`
+	}{
+		Mode:       string(modeConnected),
+		Components: rows,
+	}
+	if offline {
+		view.Mode = string(modeOffline)
+	}
+
+	data, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+var listColumns = []string{"id", "name", "status", "zone", "language", "location"}
+
+func listRecord(row listRow) []string {
+	return []string{row.ID, row.Name, row.Status, row.Zone, row.Language, row.Location}
+}
+
+func writeListCSV(w io.Writer, rows []listRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(listColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(listRecord(row)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeListTable(w io.Writer, rows []listRow) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.ToUpper(strings.Join(listColumns, "\t")))
+	for _, row := range rows {
+		record := listRecord(row)
+		for i, value := range record {
+			if value == "" {
+				record[i] = "-"
+			}
+		}
+		fmt.Fprintln(table, strings.Join(record, "\t"))
+	}
+	_ = table.Flush()
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
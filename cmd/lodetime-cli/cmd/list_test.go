@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
+)
+
+func TestFilterComponentsByZoneLanguageAndStatus(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeFiles(t, projectRoot, map[string]string{"tools/sync/main.go": "package main\n"})
+
+	zones := map[string]model.Zone{
+		"core":  {Paths: []string{"lib/"}},
+		"tools": {Paths: []string{"tools/"}},
+	}
+	components := []client.Component{
+		{ID: "graph", Status: "implemented", Location: "lib/graph/", Language: "elixir"},
+		{ID: "watcher", Status: "planned", Location: "lib/watcher/"},
+		{ID: "sync", Status: "implementing", Location: "tools/sync/"},
+	}
+
+	rows := filterComponents(components, zones, projectRoot, listFilter{zone: "core"})
+	if len(rows) != 2 || rows[0].ID != "graph" || rows[1].ID != "watcher" {
+		t.Fatalf("unexpected core rows: %+v", rows)
+	}
+
+	rows = filterComponents(components, zones, projectRoot, listFilter{language: "go"})
+	if len(rows) != 1 || rows[0].ID != "sync" || rows[0].Zone != "tools" {
+		t.Fatalf("expected detected go component in tools zone, got %+v", rows)
+	}
+
+	rows = filterComponents(components, zones, projectRoot, listFilter{status: "planned"})
+	if len(rows) != 1 || rows[0].ID != "watcher" {
+		t.Fatalf("unexpected planned rows: %+v", rows)
+	}
+}
+
+func TestWriteListCSV(t *testing.T) {
+	rows := []listRow{
+		{Component: client.Component{ID: "cli", Name: "LodeTime CLI, Go", Status: "implemented", Location: "cmd/lodetime-cli/"}, Zone: "cli"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeListCSV(&buf, rows); err != nil {
+		t.Fatalf("writeListCSV error: %v", err)
+	}
+
+	want := "id,name,status,zone,language,location\ncli,\"LodeTime CLI, Go\",implemented,cli,,cmd/lodetime-cli/\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestComponentLanguagePrefersDeclared(t *testing.T) {
+	projectRoot := t.TempDir()
+	writeFiles(t, projectRoot, map[string]string{"tools/main.go": "package main\n"})
+
+	if got := componentLanguage(projectRoot, client.Component{Location: "tools/"}); got != "go" {
+		t.Fatalf("expected detected go, got %q", got)
+	}
+	if got := componentLanguage(projectRoot, client.Component{Location: "tools/", Language: "elixir"}); got != "elixir" {
+		t.Fatalf("expected declared elixir, got %q", got)
+	}
+	if got := componentLanguage(projectRoot, client.Component{}); got != "" {
+		t.Fatalf("expected no language without a location, got %q", got)
+	}
+}
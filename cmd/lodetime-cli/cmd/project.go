@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
+)
+
+// loadProject loads .lodetime/ for offline commands. Warnings such as unknown
+// keys are shown with --verbose.
+func loadProject(lodeDir string) (*model.Project, error) {
+	project, err := model.Load(lodeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if verbose {
+		for _, warning := range project.Warnings {
+			fmt.Fprintln(os.Stderr, "Warning:", warning)
+		}
+	}
+
+	return project, nil
+}
+
+// loadComponents returns every component spec, sorted by ID, in the same
+// shape the runtime reports them.
+func loadComponents(lodeDir string) ([]client.Component, error) {
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		return nil, err
+	}
+	return clientComponents(project.Components), nil
+}
+
+func clientComponents(components []model.Component) []client.Component {
+	converted := make([]client.Component, 0, len(components))
+	for _, component := range components {
+		converted = append(converted, client.Component{
+			ID:                  component.ID,
+			Name:                component.Name,
+			Status:              component.Status,
+			Description:         component.Description,
+			Location:            component.Location,
+			Language:            component.Language,
+			DependsOn:           component.DependsOn,
+			ImplementsContracts: component.ImplementsContracts,
+			Tests:               component.Tests,
+		})
+	}
+	return converted
+}
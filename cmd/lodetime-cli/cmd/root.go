@@ -8,7 +8,6 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -65,6 +64,20 @@ func init() {
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(componentCmd)
+	rootCmd.AddCommand(depsCmd)
+	rootCmd.AddCommand(affectedCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(tuiCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(beginWorkCmd)
+	rootCmd.AddCommand(checkpointCmd)
+	rootCmd.AddCommand(endWorkCmd)
+	rootCmd.AddCommand(hooksCmd)
+	rootCmd.AddCommand(driftCmd)
+	rootCmd.AddCommand(coverageCmd)
+	rootCmd.AddCommand(addComponentCmd)
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -125,45 +138,6 @@ var versionCmd = &cobra.Command{
 	},
 }
 
-// ============================================
-// Component Command
-// ============================================
-
-var componentCmd = &cobra.Command{
-	Use:   "component [id]",
-	Short: "Show component details",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		root := findLodeTimeRoot()
-		if root == "" {
-			color.Red("Not in a LodeTime project")
-			os.Exit(1)
-		}
-
-		id := args[0]
-		compPath := filepath.Join(root, "components", id+".yaml")
-
-		data, err := os.ReadFile(compPath)
-		if err != nil {
-			color.Red("Component not found: %s", id)
-			os.Exit(1)
-		}
-
-		var comp map[string]interface{}
-		if err := yaml.Unmarshal(data, &comp); err != nil {
-			color.Red("Error parsing component: %v", err)
-			os.Exit(1)
-		}
-
-		color.Cyan("Component: %s", id)
-		fmt.Println()
-
-		// Pretty print the component
-		output, _ := yaml.Marshal(comp)
-		fmt.Println(string(output))
-	},
-}
-
 // ============================================
 // Init Command
 // ============================================
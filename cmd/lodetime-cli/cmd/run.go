@@ -9,8 +9,9 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var runCmd = &cobra.Command{
@@ -26,11 +27,15 @@ var runCmd = &cobra.Command{
 		projectRoot := filepath.Dir(lodeDir)
 
 		endpoint := resolveEndpoint(runtimeEndpoint, lodeDir)
+		if endpoint == client.StdioEndpoint {
+			color.Yellow("The stdio endpoint starts the runtime for each command; nothing to run.")
+			return
+		}
 		if endpoint != "" {
 			if _, err := fetchStatus(endpoint, false, statusTimeout); err == nil {
 				color.Green("Runtime already running at %s", endpoint)
 				return
-			} else if !errors.Is(err, errConnect) {
+			} else if !errors.Is(err, client.ErrConnect) {
 				color.Red("Runtime detected but status failed: %v", err)
 				os.Exit(1)
 			}
@@ -47,8 +52,9 @@ var runCmd = &cobra.Command{
 
 		switch engine {
 		case "devcontainer":
-			mixCmd := exec.Command("mix", "run", "--no-halt")
+			mixCmd := exec.Command(client.DefaultRuntimeCommand[0], client.DefaultRuntimeCommand[1:]...)
 			mixCmd.Dir = projectRoot
+			mixCmd.Env = runtimeEnv(endpoint)
 			mixCmd.Stdout = os.Stdout
 			mixCmd.Stderr = os.Stderr
 
@@ -77,14 +83,17 @@ var runCmd = &cobra.Command{
 			}
 
 			color.Cyan("Starting runtime container (%s)...", container)
-			runCmd := exec.Command(
-				"docker", "run",
+			runArgs := []string{
+				"run",
 				"--rm",
 				"--name", container,
 				"-v", fmt.Sprintf("%s:/app", projectRoot),
 				"-w", "/app",
-				image,
-			)
+			}
+			if socket, ok := containerSocketEndpoint(endpoint, projectRoot); ok {
+				runArgs = append(runArgs, "-e", "LODE_RUNTIME_ENDPOINT="+socket)
+			}
+			runCmd := exec.Command("docker", append(runArgs, image)...)
 			runCmd.Stdout = os.Stdout
 			runCmd.Stderr = os.Stderr
 			if err := runCmd.Run(); err != nil {
@@ -100,6 +109,31 @@ var runCmd = &cobra.Command{
 	},
 }
 
+// runtimeEnv passes a Unix socket endpoint through to the runtime so it
+// listens where the CLI will dial. TCP endpoints keep the runtime default.
+func runtimeEnv(endpoint string) []string {
+	env := os.Environ()
+	if strings.HasPrefix(endpoint, client.UnixScheme) {
+		env = append(env, "LODE_RUNTIME_ENDPOINT="+endpoint)
+	}
+	return env
+}
+
+// containerSocketEndpoint maps a Unix socket under the project root to the
+// same path inside the runtime container, where the project is mounted at /app.
+func containerSocketEndpoint(endpoint, projectRoot string) (string, bool) {
+	if !strings.HasPrefix(endpoint, client.UnixScheme) {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(projectRoot, strings.TrimPrefix(endpoint, client.UnixScheme))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+
+	return client.UnixScheme + "/app/" + filepath.ToSlash(rel), true
+}
+
 func inDevcontainer() bool {
 	if os.Getenv("DEVCONTAINER") != "" || os.Getenv("REMOTE_CONTAINERS") != "" || os.Getenv("VSCODE_REMOTE_CONTAINERS") != "" || os.Getenv("CODESPACES") != "" {
 		return true
@@ -140,27 +174,11 @@ func resolveEngine(flagValue, lodeDir string) string {
 }
 
 func engineFromConfig(path string) (string, bool) {
-	data, err := os.ReadFile(path)
+	config, err := model.ReadConfig(path)
 	if err != nil {
 		return "", false
 	}
 
-	var config map[string]any
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return "", false
-	}
-
-	if runtime, ok := config["runtime"].(map[string]any); ok {
-		if engine, ok := runtime["engine"].(string); ok && engine != "" {
-			return strings.ToLower(engine), true
-		}
-	}
-	if engine, ok := config["runtime_engine"].(string); ok && engine != "" {
-		return strings.ToLower(engine), true
-	}
-	if engine, ok := config["engine"].(string); ok && engine != "" {
-		return strings.ToLower(engine), true
-	}
-
-	return "", false
+	engine := strings.ToLower(config.EngineSetting())
+	return engine, engine != ""
 }
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// modeFlags are the --connected/--offline/--auto flags shared by commands
+// that can answer from the runtime or from .lodetime/ directly.
+type modeFlags struct {
+	connected bool
+	offline   bool
+	auto      bool
+}
+
+func addModeFlags(cmd *cobra.Command, flags *modeFlags) {
+	cmd.Flags().BoolVar(&flags.connected, "connected", false, "require runtime connection")
+	cmd.Flags().BoolVar(&flags.offline, "offline", false, "read .lodetime/ directly")
+	cmd.Flags().BoolVar(&flags.auto, "auto", true, "auto-detect mode (default)")
+}
+
+func (f modeFlags) resolve() (statusMode, error) {
+	return resolveStatusMode(f.connected, f.offline, f.auto)
+}
+
+// dialRuntime connects to the runtime at endpoint. The stdio transport spawns
+// the runtime from the project root; its stderr is shown with --verbose.
+func dialRuntime(ctx context.Context, endpoint string, timeout time.Duration) (*client.Client, error) {
+	opts := client.Options{Timeout: timeout}
+	if lodeDir := findLodeTimeRoot(); lodeDir != "" {
+		opts.Dir = filepath.Dir(lodeDir)
+	}
+	if verbose {
+		opts.Stderr = os.Stderr
+	}
+
+	return client.Dial(ctx, endpoint, opts)
+}
+
+// queryRuntime runs query against the runtime according to mode and reports
+// whether the caller should answer offline instead. Auto mode falls back when
+// the runtime is unreachable or does not implement the command yet.
+func queryRuntime(lodeDir string, mode statusMode, name string, query func(context.Context, *client.Client) error) (bool, error) {
+	if mode == modeOffline {
+		return true, nil
+	}
+
+	ctx := context.Background()
+	err := func() error {
+		c, err := dialRuntime(ctx, resolveEndpoint(runtimeEndpoint, lodeDir), statusTimeout)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		return query(ctx, c)
+	}()
+	if err == nil {
+		return false, nil
+	}
+
+	if mode == modeAuto {
+		if errors.Is(err, client.ErrConnect) {
+			fmt.Fprintln(os.Stderr, "Warning: runtime not reachable, using offline mode")
+			return true, nil
+		}
+		if client.IsCode(err, client.CodeNotImplemented) {
+			fmt.Fprintf(os.Stderr, "Warning: runtime does not support %s yet, using offline mode\n", name)
+			return true, nil
+		}
+	}
+
+	return false, err
+}
+
+// runtimeSession keeps one runtime connection open across repeated queries,
+// for commands that refresh on an interval. A transport failure drops the
+// connection so the next query redials.
+type runtimeSession struct {
+	endpoint string
+	client   *client.Client
+}
+
+func (s *runtimeSession) query(fn func(context.Context, *client.Client) error) error {
+	ctx := context.Background()
+	if s.client == nil {
+		c, err := dialRuntime(ctx, s.endpoint, statusTimeout)
+		if err != nil {
+			return err
+		}
+		s.client = c
+	}
+
+	err := fn(ctx, s.client)
+	var responseErr *client.Error
+	if err != nil && !errors.As(err, &responseErr) {
+		s.close()
+	}
+	return err
+}
+
+func (s *runtimeSession) close() {
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/lodetime/lodetime-cli/validate"
+)
+
+// stagedScope is what lode check --staged validates: the components owning
+// staged files (code under their location or their YAML), their dependents,
+// and the tests those components declare.
+type stagedScope struct {
+	Staged     []string `json:"staged"`
+	Components []string `json:"components"`
+	Affected   []string `json:"affected"`
+	Tests      []string `json:"tests"`
+	TestsRun   bool     `json:"tests_run"`
+	Unmapped   []string `json:"unmapped,omitempty"`
+
+	// lodeFiles holds staged .lodetime/ files, relative to .lodetime/.
+	lodeFiles map[string]bool
+	// code maps a component to its staged files outside .lodetime/.
+	code map[string][]string
+	// files maps each affected component to its YAML file.
+	files map[string]string
+}
+
+// newStagedScope maps project-relative staged paths to components.
+func newStagedScope(project *model.Project, staged []string) *stagedScope {
+	components := clientComponents(project.Components)
+	scope := &stagedScope{
+		Staged:    staged,
+		Tests:     []string{},
+		lodeFiles: map[string]bool{},
+		code:      map[string][]string{},
+		files:     map[string]string{},
+	}
+
+	changed := []string{}
+	unmapped := []string{}
+	for _, path := range staged {
+		if rel, ok := strings.CutPrefix(path, ".lodetime/"); ok {
+			scope.lodeFiles[rel] = true
+			for _, component := range project.Components {
+				if component.File == rel {
+					changed = append(changed, component.ID)
+				}
+			}
+			continue
+		}
+		if id, ok := componentForPath(components, path); ok {
+			scope.code[id] = append(scope.code[id], path)
+			changed = append(changed, id)
+		} else {
+			unmapped = append(unmapped, path)
+		}
+	}
+
+	scope.Components = uniqueSorted(changed)
+	scope.Affected = affectedComponents(components, scope.Components)
+	scope.Unmapped = uniqueSorted(unmapped)
+
+	tests := []string{}
+	for _, id := range scope.Affected {
+		if component, ok := project.Component(id); ok {
+			scope.files[id] = component.File
+			tests = append(tests, component.Tests...)
+		}
+	}
+	scope.Tests = uniqueSorted(tests)
+
+	return scope
+}
+
+// relevant keeps findings about staged .lodetime/ files and affected
+// components. config.yaml findings are kept once any spec file is staged,
+// since build_order and cycles depend on every component; findings without a
+// file (such as unreadable directories) are always kept.
+func (s *stagedScope) relevant(finding validate.Finding) bool {
+	switch {
+	case finding.File == "" || s.lodeFiles[finding.File]:
+		return true
+	case finding.File == "config.yaml":
+		return len(s.lodeFiles) > 0
+	}
+	for _, file := range s.files {
+		if file == finding.File {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stagedScope) filter(findings []validate.Finding) []validate.Finding {
+	kept := []validate.Finding{}
+	for _, finding := range findings {
+		if s.relevant(finding) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
+
+// drift reports components whose code is staged while their YAML is not.
+func (s *stagedScope) drift(project *model.Project) []validate.Finding {
+	ids := make([]string, 0, len(s.code))
+	for id := range s.code {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	severity := validate.RuleSeverity(&project.Config, validate.RuleYAMLDrift, validate.SeverityWarn)
+	findings := []validate.Finding{}
+	for _, id := range ids {
+		component, ok := project.Component(id)
+		if !ok || s.lodeFiles[component.File] {
+			continue
+		}
+		findings = append(findings, validate.Finding{
+			Rule:     validate.RuleYAMLDrift,
+			Severity: severity,
+			File:     component.File,
+			Message: fmt.Sprintf("%d staged file(s) under %s but %s is not staged",
+				len(s.code[id]), component.Location, component.File),
+		})
+	}
+	return findings
+}
+
+func renderStagedScope(builder *strings.Builder, scope *stagedScope) {
+	fmt.Fprintf(builder, "  staged: %d file(s); components: %s; affected: %s\n",
+		len(scope.Staged), formatList(scope.Components), formatList(scope.Affected))
+	if len(scope.Tests) > 0 {
+		label := "tests"
+		if !scope.TestsRun {
+			label = "tests (not run)"
+		}
+		fmt.Fprintf(builder, "  %s: %s\n", label, strings.Join(scope.Tests, ", "))
+	}
+}
+
+// testRun is one command running declared tests from Dir, a project-relative
+// directory.
+type testRun struct {
+	Dir  string
+	Args []string
+}
+
+// testRuns groups declared tests (project-relative paths or globs) into the
+// commands that run them: mix test for .exs files and go test for the package
+// of each _test.go file. Tests without a runner are returned separately;
+// patterns matching nothing are left to the missing-test rule.
+func testRuns(projectRoot string, tests []string) ([]testRun, []string) {
+	exs := []string{}
+	packages := []string{}
+	unknown := []string{}
+	for _, test := range tests {
+		matches, _ := filepath.Glob(filepath.Join(projectRoot, filepath.FromSlash(test)))
+		for _, match := range matches {
+			rel, err := filepath.Rel(projectRoot, match)
+			if err != nil {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			switch {
+			case strings.HasSuffix(rel, ".exs"):
+				exs = append(exs, rel)
+			case strings.HasSuffix(rel, "_test.go"):
+				packages = append(packages, filepath.ToSlash(filepath.Dir(filepath.FromSlash(rel))))
+			default:
+				unknown = append(unknown, rel)
+			}
+		}
+	}
+
+	runs := []testRun{}
+	if exs = uniqueSorted(exs); len(exs) > 0 {
+		runs = append(runs, testRun{Dir: ".", Args: append([]string{"mix", "test"}, exs...)})
+	}
+	for _, dir := range uniqueSorted(packages) {
+		runs = append(runs, testRun{Dir: dir, Args: []string{"go", "test", "."}})
+	}
+	return runs, uniqueSorted(unknown)
+}
+
+// runTests runs each command with its output on out and reports failures as
+// test-failure findings. Tests that could not run, because no runner knows
+// them or the runner is not installed, are reported as warnings.
+func runTests(projectRoot string, runs []testRun, unknown []string, severity validate.Severity, out io.Writer) []validate.Finding {
+	findings := []validate.Finding{}
+	for _, run := range runs {
+		command := exec.Command(run.Args[0], run.Args[1:]...)
+		command.Dir = filepath.Join(projectRoot, filepath.FromSlash(run.Dir))
+		command.Stdout = out
+		command.Stderr = out
+		if err := command.Run(); errors.Is(err, exec.ErrNotFound) {
+			findings = append(findings, validate.Finding{
+				Rule:     validate.RuleTestFailure,
+				Severity: validate.SeverityWarn,
+				Message:  fmt.Sprintf("%s not found; `%s` was not run", run.Args[0], strings.Join(run.Args, " ")),
+			})
+		} else if err != nil {
+			findings = append(findings, validate.Finding{
+				Rule:     validate.RuleTestFailure,
+				Severity: severity,
+				Message:  fmt.Sprintf("`%s` in %s failed: %v", strings.Join(run.Args, " "), run.Dir, err),
+			})
+		}
+	}
+	for _, test := range unknown {
+		findings = append(findings, validate.Finding{
+			Rule:     validate.RuleTestFailure,
+			Severity: validate.SeverityWarn,
+			Message:  fmt.Sprintf("no runner for %s; it was not run", test),
+		})
+	}
+	return findings
+}
+
+// checkoutStagedLodeDir copies the staged .lodetime/ files into a temporary
+// directory so --staged validates what is about to be committed, not the
+// working tree. It returns the copy and a function that removes it; when no
+// .lodetime/ file is tracked yet, the working tree is used as is.
+func checkoutStagedLodeDir(lodeDir string) (string, func(), error) {
+	root := filepath.Dir(lodeDir)
+	prefix, err := gitOutput(root, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", nil, err
+	}
+	output, err := gitOutput(root, "ls-files", "--cached", "--", filepath.Base(lodeDir))
+	if err != nil {
+		return "", nil, err
+	}
+	files := splitLines(output)
+	if len(files) == 0 {
+		return lodeDir, func() {}, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "lode-staged-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	// checkout-index writes paths relative to the top of the repository.
+	args := append([]string{"checkout-index", "--prefix=" + tmp + string(filepath.Separator), "--"}, files...)
+	if _, err := gitOutput(root, args...); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	// Git does not track empty directories such as a fresh contracts/.
+	copyDir := filepath.Join(tmp, filepath.FromSlash(strings.TrimSpace(prefix)), filepath.Base(lodeDir))
+	entries, err := os.ReadDir(lodeDir)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if err := os.MkdirAll(filepath.Join(copyDir, entry.Name()), 0o755); err != nil {
+				cleanup()
+				return "", nil, err
+			}
+		}
+	}
+
+	return copyDir, cleanup, nil
+}
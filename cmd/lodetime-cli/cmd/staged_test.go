@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/validate"
+)
+
+func TestStagedScopeMapsComponentsAndDrift(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"config.yaml":                   "project: fixture\nschema_version: 1\nrules:\n  - {id: yaml-drift, severity: error}\n",
+		"components/config-loader.yaml": "id: config-loader\nlocation: lib/config/\ndepends_on: []\ntests: [test/config_test.exs]\n",
+		"components/graph-server.yaml":  "id: graph-server\nlocation: lib/graph/\ndepends_on: [config-loader]\ntests: [test/graph_test.exs]\n",
+		"components/cli.yaml":           "id: cli\nlocation: cmd/\ndepends_on: []\n",
+	})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	scope := newStagedScope(project, []string{"lib/config/loader.ex", "lib/graph/server.ex", ".lodetime/components/graph-server.yaml", "README.md"})
+	if got := strings.Join(scope.Components, ","); got != "config-loader,graph-server" {
+		t.Fatalf("unexpected components: %s", got)
+	}
+	if got := strings.Join(scope.Affected, ","); got != "config-loader,graph-server" {
+		t.Fatalf("unexpected affected: %s", got)
+	}
+	if got := strings.Join(scope.Tests, ","); got != "test/config_test.exs,test/graph_test.exs" {
+		t.Fatalf("unexpected tests: %s", got)
+	}
+	if got := strings.Join(scope.Unmapped, ","); got != "README.md" {
+		t.Fatalf("unexpected unmapped: %s", got)
+	}
+
+	drift := scope.drift(project)
+	if len(drift) != 1 || drift[0].File != "components/config-loader.yaml" || drift[0].Severity != validate.SeverityError {
+		t.Fatalf("expected config-loader drift as error, got %+v", drift)
+	}
+	if drift[0].Message != "1 staged file(s) under lib/config/ but components/config-loader.yaml is not staged" {
+		t.Fatalf("unexpected message: %s", drift[0].Message)
+	}
+}
+
+func TestStagedScopeFiltersFindings(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"components/config-loader.yaml": "id: config-loader\nlocation: lib/config/\ndepends_on: []\n",
+		"components/cli.yaml":           "id: cli\nlocation: cmd/\ndepends_on: []\n",
+	})
+	project, err := loadProject(lodeDir)
+	if err != nil {
+		t.Fatalf("loadProject error: %v", err)
+	}
+
+	findings := []validate.Finding{
+		{Rule: "a", File: "components/config-loader.yaml"},
+		{Rule: "b", File: "components/cli.yaml"},
+		{Rule: "c", File: "config.yaml"},
+		{Rule: "d"},
+	}
+	rules := func(findings []validate.Finding) string {
+		out := []string{}
+		for _, finding := range findings {
+			out = append(out, finding.Rule)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := rules(newStagedScope(project, []string{"lib/config/x.ex"}).filter(findings)); got != "a,d" {
+		t.Fatalf("code-only scope kept %s", got)
+	}
+	if got := rules(newStagedScope(project, []string{".lodetime/components/cli.yaml"}).filter(findings)); got != "b,c,d" {
+		t.Fatalf("spec scope kept %s", got)
+	}
+}
+
+func TestCheckoutStagedLodeDirIgnoresUnstagedEdits(t *testing.T) {
+	lodeDir := writeWorkRepo(t)
+	root := filepath.Dir(lodeDir)
+	gitCommitFiles(t, root, map[string]string{
+		"lib/config/loader.ex": "defmodule Loader do\nend\n",
+		"lib/graph/server.ex":  "defmodule Server do\nend\n",
+	})
+
+	staged := "id: config-loader\nstatus: implemented\nlocation: lib/config/\ndepends_on: []\ntests: []\n"
+	writeFiles(t, lodeDir, map[string]string{"components/config-loader.yaml": staged})
+	if output, err := exec.Command("git", "-C", root, "add", "-A").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, output)
+	}
+	writeFiles(t, lodeDir, map[string]string{"components/config-loader.yaml": "id: config-loader\nstatus: implemented\nlocation: lib/config/\ndepends_on: [missing]\n"})
+
+	checkDir, cleanup, err := checkoutStagedLodeDir(lodeDir)
+	if err != nil {
+		t.Fatalf("checkoutStagedLodeDir error: %v", err)
+	}
+	defer cleanup()
+
+	data, err := os.ReadFile(filepath.Join(checkDir, "components", "config-loader.yaml"))
+	if err != nil || string(data) != staged {
+		t.Fatalf("expected the staged YAML, got %q (%v)", data, err)
+	}
+	hasUnknownDependency := func(report *validate.Report) bool {
+		for _, finding := range report.Findings {
+			if finding.Rule == validate.RuleUnknownDependency {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasUnknownDependency(validate.Dir(lodeDir)) {
+		t.Fatalf("expected the working tree to report the unknown dependency")
+	}
+	if report := validate.DirIn(checkDir, root); hasUnknownDependency(report) {
+		t.Fatalf("expected the staged files to ignore the unstaged edit, got %+v", report.Findings)
+	}
+}
+
+func TestTestRunsGroupsByRunner(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"test/a_test.exs":    "",
+		"test/b_test.exs":    "",
+		"tools/x_test.go":    "package tools\n",
+		"tools/y_test.go":    "package tools\n",
+		"scripts/check.py":   "",
+		"test/other/c.exs":   "",
+		"test/helper_ex.txt": "",
+	})
+
+	runs, unknown := testRuns(root, []string{"test/*_test.exs", "tools/x_test.go", "tools/y_test.go", "scripts/check.py", "test/missing.exs"})
+	got := []string{}
+	for _, run := range runs {
+		got = append(got, run.Dir+": "+strings.Join(run.Args, " "))
+	}
+	if want := ".: mix test test/a_test.exs test/b_test.exs|tools: go test ."; strings.Join(got, "|") != want {
+		t.Fatalf("unexpected runs: %v", got)
+	}
+	if strings.Join(unknown, ",") != "scripts/check.py" {
+		t.Fatalf("unexpected unknown tests: %v", unknown)
+	}
+}
+
+func TestRunTestsReportsFailures(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	runs := []testRun{
+		{Dir: ".", Args: []string{"sh", "-c", "exit 0"}},
+		{Dir: ".", Args: []string{"sh", "-c", "echo boom; exit 3"}},
+		{Dir: ".", Args: []string{"lode-no-such-runner", "test"}},
+	}
+
+	var out strings.Builder
+	findings := runTests(t.TempDir(), runs, []string{"scripts/check.py"}, validate.SeverityError, &out)
+	if len(findings) != 3 {
+		t.Fatalf("expected three findings, got %+v", findings)
+	}
+	if findings[0].Severity != validate.SeverityError || !strings.Contains(findings[0].Message, "`sh -c echo boom; exit 3` in . failed: exit status 3") {
+		t.Fatalf("unexpected failure finding: %+v", findings[0])
+	}
+	if findings[1].Severity != validate.SeverityWarn || findings[1].Message != "lode-no-such-runner not found; `lode-no-such-runner test` was not run" {
+		t.Fatalf("unexpected missing runner finding: %+v", findings[1])
+	}
+	if findings[2].Severity != validate.SeverityWarn || findings[2].Message != "no runner for scripts/check.py; it was not run" {
+		t.Fatalf("unexpected runner finding: %+v", findings[2])
+	}
+	if out.String() != "boom\n" {
+		t.Fatalf("expected test output to be passed through, got %q", out.String())
+	}
+}
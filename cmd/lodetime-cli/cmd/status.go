@@ -1,23 +1,24 @@
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/fatih/color"
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 const (
-	defaultEndpoint = "127.0.0.1:9998"
-	statusTimeout   = 2 * time.Second
+	defaultEndpoint = client.DefaultEndpoint
+	statusTimeout   = client.DefaultTimeout
 )
 
 var (
@@ -25,12 +26,8 @@ var (
 	statusOffline   bool
 	statusAuto      bool
 	statusJSON      bool
-)
-
-var (
-	errConnect  = errors.New("connect")
-	errProtocol = errors.New("protocol")
-	errResponse = errors.New("response")
+	statusWatch     bool
+	statusInterval  time.Duration
 )
 
 type statusMode string
@@ -41,21 +38,14 @@ const (
 	modeOffline   statusMode = "offline"
 )
 
-type socketResponse struct {
-	Ok    bool                 `json:"ok"`
-	Data  map[string]any       `json:"data"`
-	Error *socketResponseError `json:"error"`
-}
-
-type socketResponseError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
-
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show project status",
-	Long:  "Shows the current status of your LodeTime project.",
+	Long: `Shows the current status of your LodeTime project.
+
+With --watch the status is refreshed every --interval: the human output is
+redrawn in place with changed fields marked, and --json prints one JSON
+document per line whenever the payload changes.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		lodeDir := findLodeTimeRoot()
 		if lodeDir == "" {
@@ -69,41 +59,28 @@ var statusCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		var payload map[string]any
-		offline := false
-
-		switch mode {
-		case modeConnected:
-			payload, err = fetchStatus(resolveEndpoint(runtimeEndpoint, lodeDir), verbose, statusTimeout)
-			if err != nil {
-				fmt.Fprintln(os.Stderr, "Connected status failed:", err)
+		if statusWatch {
+			if statusInterval <= 0 {
+				fmt.Fprintln(os.Stderr, "--interval must be greater than 0")
 				os.Exit(1)
 			}
+			watchStatus(lodeDir, mode, statusInterval)
+			return
+		}
 
-		case modeOffline:
-			payload, err = buildOfflineStatus(lodeDir, verbose)
-			offline = true
-			if err != nil {
+		payload, offline, err := loadStatus(lodeDir, mode, func() (map[string]any, error) {
+			return fetchStatus(resolveEndpoint(runtimeEndpoint, lodeDir), verbose, statusTimeout)
+		})
+		if mode == modeAuto && offline {
+			fmt.Fprintln(os.Stderr, "Warning: runtime not reachable, using offline mode")
+		}
+		if err != nil {
+			if offline {
 				fmt.Fprintln(os.Stderr, "Offline status failed:", err)
-				os.Exit(1)
-			}
-
-		case modeAuto:
-			payload, err = fetchStatus(resolveEndpoint(runtimeEndpoint, lodeDir), verbose, statusTimeout)
-			if err != nil {
-				if errors.Is(err, errConnect) {
-					fmt.Fprintln(os.Stderr, "Warning: runtime not reachable, using offline mode")
-					payload, err = buildOfflineStatus(lodeDir, verbose)
-					offline = true
-					if err != nil {
-						fmt.Fprintln(os.Stderr, "Offline status failed:", err)
-						os.Exit(1)
-					}
-				} else {
-					fmt.Fprintln(os.Stderr, "Connected status failed:", err)
-					os.Exit(1)
-				}
+			} else {
+				fmt.Fprintln(os.Stderr, "Connected status failed:", err)
 			}
+			os.Exit(1)
 		}
 
 		payload = ensureMode(payload, offline)
@@ -127,6 +104,26 @@ func init() {
 	statusCmd.Flags().BoolVar(&statusOffline, "offline", false, "read .lodetime/ directly")
 	statusCmd.Flags().BoolVar(&statusAuto, "auto", true, "auto-detect mode (default)")
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "output JSON only")
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "refresh until interrupted")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, "refresh interval for --watch")
+}
+
+// loadStatus returns the status payload for mode and whether it was built
+// from .lodetime/. In auto mode an unreachable runtime falls back to offline.
+// On error, offline tells which side failed.
+func loadStatus(lodeDir string, mode statusMode, fetch func() (map[string]any, error)) (map[string]any, bool, error) {
+	if mode != modeOffline {
+		payload, err := fetch()
+		if err == nil {
+			return payload, false, nil
+		}
+		if mode == modeConnected || !errors.Is(err, client.ErrConnect) {
+			return nil, false, err
+		}
+	}
+
+	payload, err := buildOfflineStatus(lodeDir, verbose)
+	return payload, true, err
 }
 
 func resolveStatusMode(connected, offline, auto bool) (statusMode, error) {
@@ -165,6 +162,10 @@ func ensureMode(payload map[string]any, offline bool) map[string]any {
 }
 
 func resolveEndpoint(flagValue, lodeDir string) string {
+	return expandEndpoint(lookupEndpoint(flagValue, lodeDir), lodeDir)
+}
+
+func lookupEndpoint(flagValue, lodeDir string) string {
 	if flagValue != "" {
 		return flagValue
 	}
@@ -188,90 +189,48 @@ func resolveEndpoint(flagValue, lodeDir string) string {
 	return defaultEndpoint
 }
 
-func endpointFromConfig(path string) (string, bool) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return "", false
-	}
-
-	var config map[string]any
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return "", false
+// expandEndpoint resolves project-relative Unix socket endpoints: a bare
+// "unix://" selects the per-project socket, and relative socket paths are
+// taken relative to the project root.
+func expandEndpoint(endpoint, lodeDir string) string {
+	if !strings.HasPrefix(endpoint, client.UnixScheme) || lodeDir == "" {
+		return endpoint
 	}
 
-	if runtime, ok := config["runtime"].(map[string]any); ok {
-		if endpoint, ok := runtime["endpoint"].(string); ok && endpoint != "" {
-			return endpoint, true
-		}
-	}
-	if endpoint, ok := config["runtime_endpoint"].(string); ok && endpoint != "" {
-		return endpoint, true
-	}
-	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
-		return endpoint, true
+	path := strings.TrimPrefix(endpoint, client.UnixScheme)
+	switch {
+	case path == "":
+		path = client.ProjectSocketPath(lodeDir)
+	case !filepath.IsAbs(path):
+		path = filepath.Join(filepath.Dir(lodeDir), path)
 	}
 
-	return "", false
+	return client.UnixScheme + path
 }
 
-func fetchStatus(endpoint string, verbose bool, timeout time.Duration) (map[string]any, error) {
-	conn, err := net.DialTimeout("tcp", endpoint, timeout)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errConnect, err)
-	}
-	defer conn.Close()
-
-	_ = conn.SetDeadline(time.Now().Add(timeout))
-
-	request := map[string]any{
-		"cmd":     "status",
-		"verbose": verbose,
-	}
-	payload, err := json.Marshal(request)
+func endpointFromConfig(path string) (string, bool) {
+	config, err := model.ReadConfig(path)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errProtocol, err)
+		return "", false
 	}
 
-	if _, err := conn.Write(append(payload, '\n')); err != nil {
-		return nil, fmt.Errorf("%w: %v", errProtocol, err)
-	}
+	endpoint := config.EndpointSetting()
+	return endpoint, endpoint != ""
+}
 
-	reader := bufio.NewReader(conn)
-	line, err := reader.ReadString('\n')
+func fetchStatus(endpoint string, verbose bool, timeout time.Duration) (map[string]any, error) {
+	ctx := context.Background()
+	c, err := dialRuntime(ctx, endpoint, timeout)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errProtocol, err)
-	}
-
-	var response socketResponse
-	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &response); err != nil {
-		return nil, fmt.Errorf("%w: %v", errProtocol, err)
-	}
-
-	if !response.Ok {
-		if response.Error != nil {
-			return nil, fmt.Errorf("%w: %s: %s", errResponse, response.Error.Code, response.Error.Message)
-		}
-		return nil, fmt.Errorf("%w: unknown error", errResponse)
-	}
-
-	if response.Data == nil {
-		response.Data = map[string]any{}
+		return nil, err
 	}
+	defer c.Close()
 
-	return response.Data, nil
+	return c.Status(ctx, verbose)
 }
 
 func buildOfflineStatus(lodeDir string, verbose bool) (map[string]any, error) {
-	config, err := loadConfig(lodeDir)
-	if err != nil {
-		return nil, err
-	}
-
-	componentCount, err := countYamlFiles(filepath.Join(lodeDir, "components"))
-	if err != nil {
-		return nil, err
-	}
-	contractCount, err := countYamlFiles(filepath.Join(lodeDir, "contracts"))
+	project, err := loadProject(lodeDir)
 	if err != nil {
 		return nil, err
 	}
@@ -280,13 +239,13 @@ func buildOfflineStatus(lodeDir string, verbose bool) (map[string]any, error) {
 		"mode":   string(modeOffline),
 		"source": "offline",
 		"graph": map[string]any{
-			"component_count": componentCount,
-			"contract_count":  contractCount,
+			"component_count": len(project.Components),
+			"contract_count":  len(project.Contracts),
 		},
 	}
 
 	if verbose {
-		if summary := buildConfigSummary(config); summary != nil {
+		if summary := buildConfigSummary(&project.Config); summary != nil {
 			payload["config_summary"] = summary
 		}
 	}
@@ -294,52 +253,22 @@ func buildOfflineStatus(lodeDir string, verbose bool) (map[string]any, error) {
 	return payload, nil
 }
 
-func loadConfig(lodeDir string) (map[string]any, error) {
-	configPath := filepath.Join(lodeDir, "config.yaml")
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, err
-	}
-
-	var config map[string]any
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
-	}
-
-	return config, nil
-}
-
-func countYamlFiles(dir string) (int, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return 0, err
-	}
-
-	count := 0
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		if filepath.Ext(entry.Name()) == ".yaml" {
-			count++
-		}
-	}
-
-	return count, nil
-}
-
-func buildConfigSummary(config map[string]any) map[string]any {
+func buildConfigSummary(config *model.Config) map[string]any {
 	summary := map[string]any{}
-	if profile, ok := config["active_profile"].(string); ok && profile != "" {
-		summary["active_profile"] = profile
+	if config.ActiveProfile != "" {
+		summary["active_profile"] = config.ActiveProfile
 	}
 
-	if watched := countWatchedPaths(config); watched >= 0 {
+	if config.Has("zones") {
+		watched := 0
+		for _, zone := range config.Zones {
+			watched += len(zone.Paths)
+		}
 		summary["watched_paths_count"] = watched
 	}
 
-	if ignored := countIgnoredPaths(config); ignored >= 0 {
-		summary["ignored_paths_count"] = ignored
+	if ignore := config.Triggers.FileSystem.Ignore; ignore != nil {
+		summary["ignored_paths_count"] = len(ignore)
 	}
 
 	if len(summary) == 0 {
@@ -349,45 +278,6 @@ func buildConfigSummary(config map[string]any) map[string]any {
 	return summary
 }
 
-func countWatchedPaths(config map[string]any) int {
-	zones, ok := config["zones"].(map[string]any)
-	if !ok {
-		return -1
-	}
-
-	total := 0
-	for _, zone := range zones {
-		zoneMap, ok := zone.(map[string]any)
-		if !ok {
-			continue
-		}
-		paths, ok := zoneMap["paths"].([]any)
-		if !ok {
-			continue
-		}
-		total += len(paths)
-	}
-
-	return total
-}
-
-func countIgnoredPaths(config map[string]any) int {
-	triggers, ok := config["triggers"].(map[string]any)
-	if !ok {
-		return -1
-	}
-	fileSystem, ok := triggers["file_system"].(map[string]any)
-	if !ok {
-		return -1
-	}
-	ignore, ok := fileSystem["ignore"].([]any)
-	if !ok {
-		return -1
-	}
-
-	return len(ignore)
-}
-
 func renderStatusJSON(payload map[string]any, verbose bool, offline bool) (string, error) {
 	if !verbose {
 		delete(payload, "config_summary")
@@ -415,6 +305,12 @@ func renderStatusJSON(payload map[string]any, verbose bool, offline bool) (strin
 }
 
 func renderStatusHuman(payload map[string]any, verbose bool, offline bool) string {
+	return renderStatusHumanChanges(payload, verbose, offline, nil)
+}
+
+// renderStatusHumanChanges renders like renderStatusHuman and marks the
+// fields whose dotted payload path (e.g. "queue.pending_count") is in changed.
+func renderStatusHumanChanges(payload map[string]any, verbose bool, offline bool, changed map[string]bool) string {
 	builder := &strings.Builder{}
 
 	mode := stringValue(payload["mode"], "unknown")
@@ -426,18 +322,18 @@ func renderStatusHuman(payload map[string]any, verbose bool, offline bool) strin
 	}
 
 	if !offline {
-		fmt.Fprintf(builder, "Runtime State: %s\n", stringValue(payload["runtime_state"], "n/a"))
-		fmt.Fprintf(builder, "Runtime Version: %s\n", stringValue(payload["runtime_version"], "n/a"))
+		writeField(builder, "", "Runtime State", stringValue(payload["runtime_state"], "n/a"), changed["runtime_state"])
+		writeField(builder, "", "Runtime Version", stringValue(payload["runtime_version"], "n/a"), changed["runtime_version"])
 	}
 
-	writeSection(builder, "Graph", []sectionField{
+	writeSectionChanges(builder, "Graph", "graph", changed, []sectionField{
 		{key: "component_count", value: mapValue(payload["graph"])["component_count"]},
 		{key: "contract_count", value: mapValue(payload["graph"])["contract_count"]},
 	})
 
 	if !offline {
 		lastError := mapValue(payload["last_error"])
-		writeSection(builder, "Last Error", []sectionField{
+		writeSectionChanges(builder, "Last Error", "last_error", changed, []sectionField{
 			{key: "count", value: lastError["count"]},
 			{key: "last_at", value: lastError["last_at"]},
 			{key: "last_message", value: lastError["last_message"]},
@@ -447,7 +343,7 @@ func renderStatusHuman(payload map[string]any, verbose bool, offline bool) strin
 	if verbose {
 		if offline {
 			if summary := mapValue(payload["config_summary"]); len(summary) > 0 {
-				writeSection(builder, "Config Summary", []sectionField{
+				writeSectionChanges(builder, "Config Summary", "config_summary", changed, []sectionField{
 					{key: "active_profile", value: summary["active_profile"]},
 					{key: "watched_paths_count", value: summary["watched_paths_count"]},
 					{key: "ignored_paths_count", value: summary["ignored_paths_count"]},
@@ -457,40 +353,40 @@ func renderStatusHuman(payload map[string]any, verbose bool, offline bool) strin
 		}
 
 		queue := mapValue(payload["queue"])
-		writeSection(builder, "Queue", []sectionField{
+		writeSectionChanges(builder, "Queue", "queue", changed, []sectionField{
 			{key: "pending_count", value: queue["pending_count"]},
 			{key: "oldest_age", value: queue["oldest_age"]},
 			{key: "last_checkpoint_at", value: queue["last_checkpoint_at"]},
 		})
 
 		graph := mapValue(payload["graph"])
-		writeSection(builder, "Graph Details", []sectionField{
+		writeSectionChanges(builder, "Graph Details", "graph", changed, []sectionField{
 			{key: "hash", value: graph["hash"]},
 			{key: "last_change_at", value: graph["last_change_at"]},
 		})
 
 		lastCheck := mapValue(payload["last_check"])
-		writeSection(builder, "Last Check", []sectionField{
+		writeSectionChanges(builder, "Last Check", "last_check", changed, []sectionField{
 			{key: "status", value: lastCheck["status"]},
 			{key: "at", value: lastCheck["at"]},
 			{key: "warnings_open", value: lastCheck["warnings_open"]},
 		})
 
 		configSummary := mapValue(payload["config_summary"])
-		writeSection(builder, "Config Summary", []sectionField{
+		writeSectionChanges(builder, "Config Summary", "config_summary", changed, []sectionField{
 			{key: "active_profile", value: configSummary["active_profile"]},
 			{key: "watched_paths_count", value: configSummary["watched_paths_count"]},
 			{key: "ignored_paths_count", value: configSummary["ignored_paths_count"]},
 		})
 
 		tools := mapValue(payload["tools"])
-		writeSection(builder, "Tools", []sectionField{
+		writeSectionChanges(builder, "Tools", "tools", changed, []sectionField{
 			{key: "enabled", value: tools["enabled"]},
 			{key: "last_run", value: tools["last_run"]},
 		})
 
 		errorsSection := mapValue(payload["errors"])
-		writeSection(builder, "Errors by Process", []sectionField{
+		writeSectionChanges(builder, "Errors by Process", "errors", changed, []sectionField{
 			{key: "by_process", value: errorsSection["by_process"]},
 		})
 	}
@@ -504,11 +400,46 @@ type sectionField struct {
 }
 
 func writeSection(builder *strings.Builder, title string, fields []sectionField) {
+	writeSectionChanges(builder, title, "", nil, fields)
+}
+
+// writeSectionChanges marks fields whose path (prefix.key) is in changed.
+func writeSectionChanges(builder *strings.Builder, title, prefix string, changed map[string]bool, fields []sectionField) {
 	fmt.Fprintln(builder)
 	fmt.Fprintln(builder, title)
 	for _, field := range fields {
-		fmt.Fprintf(builder, "  %s: %s\n", field.key, formatValue(field.value))
+		writeField(builder, "  ", field.key, formatValue(field.value), changedUnder(changed, prefix+"."+field.key))
+	}
+}
+
+var changedColor = color.New(color.FgYellow, color.Bold)
+
+// writeField writes "key: value" after indent. A changed value is
+// highlighted and marked with "*" in the indent (or before the key when there
+// is no indent).
+func writeField(builder *strings.Builder, indent, key, value string, changed bool) {
+	line := fmt.Sprintf("%s%s: %s", indent, key, value)
+	if !changed {
+		fmt.Fprintln(builder, line)
+		return
+	}
+	if indent == "" {
+		line = "* " + line
+	} else {
+		line = "*" + line[1:]
+	}
+	fmt.Fprintln(builder, changedColor.Sprint(line))
+}
+
+// changedUnder reports whether path or any path nested below it changed, so
+// a map-valued field such as errors.by_process is marked when an entry moves.
+func changedUnder(changed map[string]bool, path string) bool {
+	for key := range changed {
+		if key == path || strings.HasPrefix(key, path+".") {
+			return true
+		}
 	}
+	return false
 }
 
 func mapValue(value any) map[string]any {
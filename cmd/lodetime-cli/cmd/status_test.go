@@ -185,3 +185,24 @@ func TestRenderStatusJSONOfflineOmitsRuntimeFields(t *testing.T) {
 		t.Fatalf("expected last_error omitted, got: %s", output)
 	}
 }
+
+func TestResolveEndpointExpandsUnixSocket(t *testing.T) {
+	t.Setenv("LODE_RUNTIME_ENDPOINT", "")
+	lodeDir := filepath.Join(t.TempDir(), ".lodetime")
+	if err := os.MkdirAll(lodeDir, 0o755); err != nil {
+		t.Fatalf("mkdir .lodetime: %v", err)
+	}
+
+	if got := resolveEndpoint("unix://", lodeDir); got != "unix://"+filepath.Join(lodeDir, "lode.sock") {
+		t.Fatalf("expected project socket, got %s", got)
+	}
+	if got := resolveEndpoint("unix:///var/run/lode.sock", lodeDir); got != "unix:///var/run/lode.sock" {
+		t.Fatalf("expected absolute socket kept, got %s", got)
+	}
+
+	writeFiles(t, lodeDir, map[string]string{"config.yaml": "runtime:\n  endpoint: unix://run/lode.sock\n"})
+	want := "unix://" + filepath.Join(filepath.Dir(lodeDir), "run", "lode.sock")
+	if got := resolveEndpoint("", lodeDir); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
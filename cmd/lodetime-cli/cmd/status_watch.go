@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/terminal"
+)
+
+// watchStatus refreshes status every interval until interrupted. Runtime
+// failures are shown and retried rather than ending the watch.
+func watchStatus(lodeDir string, mode statusMode, interval time.Duration) {
+	session := &runtimeSession{endpoint: resolveEndpoint(runtimeEndpoint, lodeDir)}
+	defer session.close()
+
+	watcher := &statusWatcher{redraw: !statusJSON && terminal.IsTerminal(int(os.Stdout.Fd()))}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		payload, offline, err := loadStatus(lodeDir, mode, func() (map[string]any, error) {
+			var payload map[string]any
+			err := session.query(func(ctx context.Context, c *client.Client) error {
+				var err error
+				payload, err = c.Status(ctx, verbose)
+				return err
+			})
+			return payload, err
+		})
+
+		var output string
+		if statusJSON {
+			output = watcher.nextJSON(payload, offline, err)
+		} else {
+			output = watcher.nextHuman(payload, offline, err, interval, time.Now())
+		}
+		fmt.Print(output)
+
+		<-ticker.C
+	}
+}
+
+// statusWatcher turns successive status payloads into watch output.
+type statusWatcher struct {
+	redraw   bool
+	previous map[string]string
+	lastJSON string
+	lastErr  string
+}
+
+// nextJSON returns one compact JSON line when the payload differs from the
+// last one printed, or "" when nothing changed. Errors go to stderr once per
+// distinct message.
+func (w *statusWatcher) nextJSON(payload map[string]any, offline bool, err error) string {
+	if err != nil {
+		if message := err.Error(); message != w.lastErr {
+			w.lastErr = message
+			fmt.Fprintln(os.Stderr, "Status failed:", message)
+		}
+		return ""
+	}
+	w.lastErr = ""
+
+	output, err := renderStatusJSON(ensureMode(payload, offline), verbose, offline)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+		return ""
+	}
+	compact := &bytes.Buffer{}
+	if err := json.Compact(compact, []byte(output)); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+		return ""
+	}
+
+	line := compact.String()
+	if line == w.lastJSON {
+		return ""
+	}
+	w.lastJSON = line
+	return line + "\n"
+}
+
+// nextHuman returns the full human view with fields that changed since the
+// previous refresh marked. When redrawing, the screen is cleared first.
+func (w *statusWatcher) nextHuman(payload map[string]any, offline bool, err error, interval time.Duration, now time.Time) string {
+	header := fmt.Sprintf("Every %s · updated %s · Ctrl-C to stop\n\n", interval, now.Format("15:04:05"))
+	if w.redraw {
+		header = terminal.ClearScreen + header
+	}
+
+	if err != nil {
+		return header + fmt.Sprintf("Status failed: %v\n", err)
+	}
+
+	payload = ensureMode(payload, offline)
+	current := flattenPayload(payload)
+	changed := map[string]bool{}
+	if w.previous != nil {
+		changed = changedPaths(w.previous, current)
+	}
+	w.previous = current
+
+	return header + renderStatusHumanChanges(payload, verbose, offline, changed)
+}
+
+// flattenPayload maps dotted paths to formatted leaf values, e.g.
+// "queue.pending_count" → "3".
+func flattenPayload(payload map[string]any) map[string]string {
+	flat := map[string]string{}
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		if nested, ok := value.(map[string]any); ok {
+			for key, child := range nested {
+				walk(joinPath(prefix, key), child)
+			}
+			return
+		}
+		flat[prefix] = formatValue(value)
+	}
+	walk("", payload)
+	return flat
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// changedPaths returns the paths added, removed or changed between two
+// flattened payloads.
+func changedPaths(previous, current map[string]string) map[string]bool {
+	changed := map[string]bool{}
+	for path, value := range current {
+		if old, ok := previous[path]; !ok || old != value {
+			changed[path] = true
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed[path] = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func statusFixture(pending int, hash string) map[string]any {
+	return map[string]any{
+		"runtime_state": "running",
+		"queue":         map[string]any{"pending_count": pending},
+		"graph":         map[string]any{"component_count": 9, "hash": hash},
+		"last_error":    map[string]any{"count": 0},
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	previous := flattenPayload(statusFixture(0, "abc"))
+	current := flattenPayload(statusFixture(2, "abc"))
+	delete(current, "last_error.count")
+
+	paths := []string{}
+	for path := range changedPaths(previous, current) {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if got := strings.Join(paths, ","); got != "last_error.count,queue.pending_count" {
+		t.Fatalf("unexpected changed paths: %s", got)
+	}
+}
+
+func TestStatusWatcherHumanMarksChanges(t *testing.T) {
+	color.NoColor = true
+	verbose = true
+	defer func() { verbose = false }()
+
+	watcher := &statusWatcher{}
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	first := watcher.nextHuman(statusFixture(0, "abc"), false, nil, 2*time.Second, now)
+	if strings.Contains(first, "*") {
+		t.Fatalf("expected no marks on first refresh, got: %s", first)
+	}
+	if !strings.HasPrefix(first, "Every 2s · updated 15:04:05") {
+		t.Fatalf("unexpected header: %s", first)
+	}
+
+	second := watcher.nextHuman(statusFixture(3, "def"), false, nil, 2*time.Second, now)
+	for _, want := range []string{"* pending_count: 3", "* hash: def", "  component_count: 9", "Runtime State: running"} {
+		if !strings.Contains(second, want) {
+			t.Fatalf("expected %q in output, got: %s", want, second)
+		}
+	}
+
+	failed := watcher.nextHuman(nil, false, errors.New("boom"), 2*time.Second, now)
+	if !strings.Contains(failed, "Status failed: boom") {
+		t.Fatalf("expected error in output, got: %s", failed)
+	}
+}
+
+func TestStatusWatcherJSONOnlyOnChange(t *testing.T) {
+	verbose = true
+	defer func() { verbose = false }()
+
+	watcher := &statusWatcher{}
+
+	first := watcher.nextJSON(statusFixture(0, "abc"), false, nil)
+	if !strings.HasSuffix(first, "}\n") || strings.Count(first, "\n") != 1 {
+		t.Fatalf("expected one JSON line, got %q", first)
+	}
+	if again := watcher.nextJSON(statusFixture(0, "abc"), false, nil); again != "" {
+		t.Fatalf("expected no output for unchanged payload, got %q", again)
+	}
+	if changed := watcher.nextJSON(statusFixture(1, "abc"), false, nil); !strings.Contains(changed, `"pending_count":1`) {
+		t.Fatalf("expected changed payload, got %q", changed)
+	}
+}
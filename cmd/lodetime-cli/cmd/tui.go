@@ -0,0 +1,558 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/model"
+	"github.com/lodetime/lodetime-cli/terminal"
+	"github.com/spf13/cobra"
+)
+
+var tuiInterval time.Duration
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen project dashboard",
+	Long: `Shows component health by status, the runtime queue, the last check,
+errors by process and a navigable dependency tree, refreshed every --interval.
+When the runtime is not reachable the dashboard keeps showing .lodetime/ and
+reconnects on the next refresh.
+
+Keys: ↑/↓ or k/j select a component, tab switches between dependencies and
+dependents, c triggers a checkpoint, r refreshes, q quits.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+		if tuiInterval <= 0 {
+			fmt.Fprintln(os.Stderr, "--interval must be greater than 0")
+			os.Exit(1)
+		}
+
+		stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+		if !terminal.IsTerminal(stdin) || !terminal.IsTerminal(stdout) {
+			fmt.Fprintln(os.Stderr, "lode tui needs an interactive terminal; use 'lode status --watch' instead")
+			os.Exit(1)
+		}
+
+		state, err := terminal.MakeRaw(stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to set up terminal:", err)
+			os.Exit(1)
+		}
+
+		d := &dashboard{
+			runtimeSession: runtimeSession{endpoint: resolveEndpoint(runtimeEndpoint, lodeDir)},
+			lodeDir:        lodeDir,
+		}
+		err = d.run(stdin, stdout)
+
+		fmt.Print(terminal.ShowCursor + terminal.ExitAltScreen)
+		_ = terminal.Restore(stdin, state)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	tuiCmd.Flags().DurationVar(&tuiInterval, "interval", 2*time.Second, "refresh interval")
+}
+
+type tuiKey int
+
+const (
+	keyQuit tuiKey = iota + 1
+	keyUp
+	keyDown
+	keyToggle
+	keyCheckpoint
+	keyRefresh
+)
+
+// parseKeys decodes a chunk of raw terminal input. Unknown bytes and escape
+// sequences are ignored.
+func parseKeys(input []byte) []tuiKey {
+	keys := []tuiKey{}
+	for i := 0; i < len(input); i++ {
+		switch input[i] {
+		case 'q', 3: // 3 is ctrl-c; ISIG is off in raw mode
+			keys = append(keys, keyQuit)
+		case 'k':
+			keys = append(keys, keyUp)
+		case 'j':
+			keys = append(keys, keyDown)
+		case '\t':
+			keys = append(keys, keyToggle)
+		case 'c':
+			keys = append(keys, keyCheckpoint)
+		case 'r':
+			keys = append(keys, keyRefresh)
+		case 0x1b:
+			if i+2 < len(input) && input[i+1] == '[' {
+				switch input[i+2] {
+				case 'A':
+					keys = append(keys, keyUp)
+				case 'B':
+					keys = append(keys, keyDown)
+				}
+				i += 2
+			}
+		}
+	}
+	return keys
+}
+
+// dashboard is the state behind lode tui.
+type dashboard struct {
+	runtimeSession
+	lodeDir string
+
+	status     map[string]any
+	components []client.Component
+	runtimeErr error
+	loadErr    error
+	updatedAt  time.Time
+
+	selected int
+	reverse  bool
+	message  string
+}
+
+// tuiJob runs on the worker goroutine and returns the update to apply on the
+// input loop.
+type tuiJob func() func()
+
+func (d *dashboard) run(stdin, stdout int) error {
+	done := make(chan struct{})
+
+	// The key reader only reads once stdin has input and checks done between
+	// short waits, so it stops with the dashboard instead of swallowing the
+	// shell's next keystrokes; run waits for it before the terminal is
+	// restored.
+	input := make(chan []byte)
+	stopped := make(chan struct{})
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+	go func() {
+		defer close(stopped)
+		defer close(input)
+		buf := make([]byte, 64)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			ready, err := terminal.Readable(stdin, 100*time.Millisecond)
+			if err != nil {
+				return
+			}
+			if !ready {
+				continue
+			}
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			select {
+			case input <- append([]byte{}, buf[:n]...):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Runtime queries run on one worker, which owns the session, so a slow
+	// runtime never freezes the keys; results come back through updates.
+	jobs := make(chan tuiJob, 4)
+	updates := make(chan func())
+	go func() {
+		defer d.close()
+		for {
+			select {
+			case job := <-jobs:
+				select {
+				case updates <- job():
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	pending := 0
+	submit := func(job tuiJob) bool {
+		select {
+		case jobs <- job:
+			pending++
+			return true
+		default:
+			return false
+		}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(tuiInterval)
+	defer ticker.Stop()
+
+	fmt.Print(terminal.EnterAltScreen + terminal.HideCursor + terminal.ClearScreen)
+	submit(d.refreshJob())
+	d.draw(stdout)
+
+	for {
+		select {
+		case chunk, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, key := range parseKeys(chunk) {
+				switch key {
+				case keyQuit:
+					return nil
+				case keyRefresh:
+					submit(d.refreshJob())
+				case keyCheckpoint:
+					if submit(d.checkpointJob()) {
+						d.message = "checkpoint: running..."
+					} else {
+						d.message = "checkpoint: busy, try again"
+					}
+				default:
+					d.handle(key)
+				}
+			}
+		case apply := <-updates:
+			pending--
+			apply()
+		case <-ticker.C:
+			// Skip ticks while the runtime is still answering.
+			if pending == 0 {
+				submit(d.refreshJob())
+			}
+		case <-signals:
+			return nil
+		}
+		d.draw(stdout)
+	}
+}
+
+func (d *dashboard) draw(stdout int) {
+	width, height, err := terminal.Size(stdout)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+
+	lines := d.render(width, height)
+	fmt.Print(terminal.Home + strings.Join(lines, terminal.ClearLine+"\n") + terminal.ClearLine + terminal.ClearToEnd)
+}
+
+func (d *dashboard) handle(key tuiKey) {
+	switch key {
+	case keyUp:
+		if d.selected > 0 {
+			d.selected--
+		}
+	case keyDown:
+		if d.selected < len(d.components)-1 {
+			d.selected++
+		}
+	case keyToggle:
+		d.reverse = !d.reverse
+	}
+}
+
+// dashboardSnapshot is what one refresh fetched.
+type dashboardSnapshot struct {
+	at         time.Time
+	status     map[string]any
+	components []client.Component // nil when neither source answered
+	runtimeErr error
+	loadErr    error
+}
+
+func (d *dashboard) refreshJob() tuiJob {
+	return func() func() {
+		snapshot := d.fetch()
+		return func() { d.apply(snapshot) }
+	}
+}
+
+// fetch reloads component specs from .lodetime/ and, when the runtime is
+// reachable, replaces them with the runtime's view and fetches its status.
+// It runs on the worker and only touches the runtime session.
+func (d *dashboard) fetch() dashboardSnapshot {
+	snapshot := dashboardSnapshot{at: time.Now()}
+
+	project, err := model.Load(d.lodeDir)
+	snapshot.loadErr = err
+	if err == nil {
+		snapshot.components = clientComponents(project.Components)
+	}
+
+	snapshot.runtimeErr = d.query(func(ctx context.Context, c *client.Client) error {
+		status, err := c.Status(ctx, true)
+		if err != nil {
+			return err
+		}
+		snapshot.status = status
+
+		components, err := c.List(ctx, "")
+		if err == nil {
+			snapshot.components = components
+		} else if !client.IsCode(err, client.CodeNotImplemented) {
+			return err
+		}
+		return nil
+	})
+	if snapshot.runtimeErr != nil {
+		snapshot.status = nil
+	}
+	return snapshot
+}
+
+// apply shows a snapshot, keeping the previous components when it has none.
+func (d *dashboard) apply(snapshot dashboardSnapshot) {
+	d.updatedAt = snapshot.at
+	d.status = snapshot.status
+	d.runtimeErr = snapshot.runtimeErr
+	d.loadErr = snapshot.loadErr
+	if snapshot.components != nil {
+		d.components = snapshot.components
+	}
+
+	sort.Slice(d.components, func(i, j int) bool {
+		return d.components[i].ID < d.components[j].ID
+	})
+	if d.selected >= len(d.components) {
+		d.selected = max(len(d.components)-1, 0)
+	}
+}
+
+// checkpointJob asks the runtime for a checkpoint and, when it ran, refreshes
+// in the same job.
+func (d *dashboard) checkpointJob() tuiJob {
+	return func() func() {
+		var report *client.WorkReport
+		err := d.query(func(ctx context.Context, c *client.Client) error {
+			var err error
+			report, err = c.Checkpoint(ctx, "")
+			return err
+		})
+
+		at := time.Now().Format("15:04:05")
+		var message string
+		switch {
+		case client.IsCode(err, client.CodeNotImplemented):
+			message = "checkpoint: runtime does not support checkpoint yet"
+		case err != nil:
+			message = fmt.Sprintf("checkpoint failed at %s: %v", at, err)
+		default:
+			message = fmt.Sprintf("checkpoint %s at %s", stringValue(report.Status, "requested"), at)
+			snapshot := d.fetch()
+			return func() {
+				d.message = message
+				d.apply(snapshot)
+			}
+		}
+		return func() { d.message = message }
+	}
+}
+
+// render lays the dashboard out as at most height lines of at most width
+// columns.
+func (d *dashboard) render(width, height int) []string {
+	lines := []string{}
+	add := func(format string, args ...any) {
+		lines = append(lines, fit(fmt.Sprintf(format, args...), width))
+	}
+
+	connection := "connected"
+	switch {
+	case d.updatedAt.IsZero():
+		connection = "connecting"
+	case d.runtimeErr != nil:
+		connection = "offline"
+	}
+	add("LodeTime Dashboard · %s · %s · updated %s", connection, d.endpoint, d.updatedAt.Format("15:04:05"))
+
+	graph := mapValue(d.status["graph"])
+	switch {
+	case d.updatedAt.IsZero():
+		add("Runtime: connecting...")
+	case d.runtimeErr != nil:
+		add("Runtime: unreachable (%v); showing .lodetime/", d.runtimeErr)
+	default:
+		add("Runtime: %s (%s)  graph hash: %s", stringValue(d.status["runtime_state"], "n/a"),
+			stringValue(d.status["runtime_version"], "n/a"), formatValue(graph["hash"]))
+	}
+	if d.loadErr != nil {
+		add("Specs: %v", d.loadErr)
+	}
+	add("")
+
+	add("Health   %s", formatStatusCounts(d.components))
+	queue := mapValue(d.status["queue"])
+	add("Queue    pending: %s  oldest: %s  last checkpoint: %s", formatValue(queue["pending_count"]),
+		formatValue(queue["oldest_age"]), formatValue(queue["last_checkpoint_at"]))
+	lastCheck := mapValue(d.status["last_check"])
+	add("Check    status: %s  at: %s  warnings open: %s", formatValue(lastCheck["status"]),
+		formatValue(lastCheck["at"]), formatValue(lastCheck["warnings_open"]))
+	add("Errors   %s", formatErrorsByProcess(d.status))
+	lastError := mapValue(d.status["last_error"])
+	if message, ok := lastError["last_message"].(string); ok && message != "" {
+		add("         last: %s", message)
+	}
+	add("")
+
+	footer := []string{
+		fit("↑/↓ select  tab dependencies/dependents  c checkpoint  r refresh  q quit", width),
+		fit(d.message, width),
+	}
+
+	if bodyHeight := height - len(lines) - len(footer); bodyHeight > 0 {
+		lines = append(lines, d.renderBody(width, bodyHeight)...)
+	}
+	for len(lines) < height-len(footer) {
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer...)
+	if len(lines) > height {
+		lines = lines[:max(height, 0)]
+	}
+	return lines
+}
+
+// renderBody shows the component list on the left and the selected
+// component's dependency tree on the right.
+func (d *dashboard) renderBody(width, height int) []string {
+	title := "Dependencies"
+	if d.reverse {
+		title = "Dependents"
+	}
+
+	idWidth := 0
+	for _, component := range d.components {
+		idWidth = max(idWidth, utf8.RuneCountInString(component.ID))
+	}
+	leftWidth := min(idWidth+len(model.StatusImplementing)+5, width/2)
+
+	left := []string{fit("Components", leftWidth)}
+	right := []string{title}
+	if len(d.components) == 0 {
+		left = append(left, fit("  (none)", leftWidth))
+	} else {
+		selected := d.components[d.selected]
+		if deps, err := buildDependencyTree(d.components, selected.ID, 0, d.reverse); err == nil {
+			right = append(right, strings.Split(strings.TrimSuffix(renderDependencyTree(deps), "\n"), "\n")...)
+		}
+
+		offset := 0
+		if d.selected >= height-1 {
+			offset = d.selected - (height - 2)
+		}
+		for i := offset; i < len(d.components) && len(left) < height; i++ {
+			component := d.components[i]
+			marker := " "
+			if i == d.selected {
+				marker = ">"
+			}
+			row := fit(fmt.Sprintf("%s %-*s  %s", marker, idWidth, component.ID, component.Status), leftWidth)
+			if i == d.selected {
+				row = terminal.Reverse + row + terminal.Reset
+			}
+			left = append(left, row)
+		}
+	}
+
+	lines := []string{}
+	for i := 0; i < height && (i < len(left) || i < len(right)); i++ {
+		row := strings.Repeat(" ", leftWidth)
+		if i < len(left) {
+			row = left[i]
+		}
+		row += " │ "
+		if i < len(right) {
+			row += fit(right[i], width-leftWidth-3)
+		}
+		lines = append(lines, row)
+	}
+	return lines
+}
+
+func formatStatusCounts(components []client.Component) string {
+	counts := map[string]int{}
+	unhealthy := 0
+	for _, component := range components {
+		counts[component.Status]++
+		if component.Health != "" && component.Health != "healthy" {
+			unhealthy++
+		}
+	}
+
+	parts := []string{}
+	for _, status := range model.Statuses {
+		parts = append(parts, fmt.Sprintf("%s %d", status, counts[status]))
+	}
+	if unhealthy > 0 {
+		parts = append(parts, fmt.Sprintf("unhealthy %d", unhealthy))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// formatErrorsByProcess renders errors.by_process, which the runtime reports
+// as process name → count.
+func formatErrorsByProcess(status map[string]any) string {
+	if status == nil {
+		return "n/a"
+	}
+	byProcess := mapValue(mapValue(status["errors"])["by_process"])
+	if len(byProcess) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(byProcess))
+	for name := range byProcess {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := []string{}
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, formatValue(byProcess[name])))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// fit pads or truncates s to exactly width runes.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	count := utf8.RuneCountInString(s)
+	if count <= width {
+		return s + strings.Repeat(" ", width-count)
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/terminal"
+)
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\x1b[Ak\t\x1b[Bcrx\x03"))
+	want := []tuiKey{keyDown, keyUp, keyUp, keyToggle, keyDown, keyCheckpoint, keyRefresh, keyQuit}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDashboardRender(t *testing.T) {
+	d := &dashboard{
+		runtimeSession: runtimeSession{endpoint: "127.0.0.1:9998"},
+		updatedAt:      time.Now(),
+		status: map[string]any{
+			"runtime_state":   "running",
+			"runtime_version": "0.1.0",
+			"queue":           map[string]any{"pending_count": 2, "oldest_age": "3s"},
+			"last_check":      map[string]any{"status": "ok"},
+			"errors":          map[string]any{"by_process": map[string]any{"test-runner": 1, "graph-server": 3}},
+		},
+		components: []client.Component{
+			{ID: "config-loader", Status: "implemented"},
+			{ID: "graph-server", Status: "implemented", DependsOn: []string{"config-loader"}, Health: "degraded"},
+			{ID: "state-server", Status: "planned", DependsOn: []string{"graph-server"}},
+		},
+		selected: 2,
+	}
+
+	lines := d.render(80, 20)
+	if len(lines) != 20 {
+		t.Fatalf("expected 20 lines, got %d", len(lines))
+	}
+	output := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"Runtime: running (0.1.0)",
+		"planned 1  implementing 0  implemented 2  deprecated 0  unhealthy 1",
+		"pending: 2  oldest: 3s",
+		"Errors   graph-server: 3  test-runner: 1",
+		terminal.Reverse + "> state-server",
+		"│ └── graph-server",
+		"│     └── config-loader",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in dashboard, got:\n%s", want, output)
+		}
+	}
+
+	d.runtimeErr = errors.New("connect: refused")
+	d.reverse = true
+	output = strings.Join(d.render(40, 20), "\n")
+	if !strings.Contains(output, "offline") || !strings.Contains(output, "Dependents") {
+		t.Fatalf("expected offline dependents view, got:\n%s", output)
+	}
+}
+
+func TestDashboardApplyKeepsComponentsWithoutSources(t *testing.T) {
+	d := &dashboard{
+		components: []client.Component{{ID: "b"}, {ID: "a"}, {ID: "c"}},
+		selected:   2,
+	}
+	if !strings.Contains(strings.Join(d.render(60, 10), "\n"), "connecting") {
+		t.Fatalf("expected connecting before the first snapshot")
+	}
+
+	d.apply(dashboardSnapshot{at: time.Now(), runtimeErr: errors.New("refused"), loadErr: errors.New("bad yaml")})
+	if len(d.components) != 3 || d.components[0].ID != "a" || d.runtimeErr == nil {
+		t.Fatalf("expected previous components kept and sorted, got %+v", d.components)
+	}
+
+	d.apply(dashboardSnapshot{at: time.Now(), components: []client.Component{{ID: "x"}}})
+	if len(d.components) != 1 || d.selected != 0 || d.loadErr != nil {
+		t.Fatalf("expected new components and clamped selection, got %+v (selected %d)", d.components, d.selected)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("abc", 5); got != "abc  " {
+		t.Fatalf("expected padding, got %q", got)
+	}
+	if got := fit("└── abcdef", 6); got != "└── a…" {
+		t.Fatalf("expected rune-aware truncation, got %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/validate"
+	"github.com/spf13/cobra"
+)
+
+var validateJSON bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate .lodetime/ without the runtime",
+	Long: `Checks required keys, schema_version, component statuses, depends_on and
+implements_contracts references, duplicate IDs, dependency cycles, build_order
+coverage and zone path overlaps. Statuses are checked against the tree
+(implemented components need code at their location, planned ones should have
+none yet) and tests: entries must exist. Exits non-zero when any error is found.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lodeDir := findLodeTimeRoot()
+		if lodeDir == "" {
+			fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+			os.Exit(1)
+		}
+
+		report := validate.Dir(lodeDir)
+
+		if validateJSON {
+			output, err := renderValidateJSON(report)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Print(renderValidateHuman(report))
+		}
+
+		if !report.OK() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "output JSON only")
+}
+
+func renderValidateJSON(report *validate.Report) (string, error) {
+	view := struct {
+		OK       bool               `json:"ok"`
+		Blocks   int                `json:"blocks"`
+		Errors   int                `json:"errors"`
+		Warnings int                `json:"warnings"`
+		Findings []validate.Finding `json:"findings"`
+	}{
+		OK:       report.OK(),
+		Blocks:   report.Count(validate.SeverityBlock),
+		Errors:   report.Count(validate.SeverityError),
+		Warnings: report.Count(validate.SeverityWarn),
+		Findings: report.Findings,
+	}
+	if view.Findings == nil {
+		view.Findings = []validate.Finding{}
+	}
+
+	data, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func renderValidateHuman(report *validate.Report) string {
+	builder := &strings.Builder{}
+
+	errorCount := report.Count(validate.SeverityError)
+	warnCount := report.Count(validate.SeverityWarn)
+	if report.OK() {
+		fmt.Fprintln(builder, "PASS: .lodetime validation succeeded")
+		if report.Project != nil {
+			fmt.Fprintf(builder, "  components: %d\n", len(report.Project.Components))
+			fmt.Fprintf(builder, "  contracts: %d\n", len(report.Project.Contracts))
+		}
+		if warnCount > 0 {
+			fmt.Fprintf(builder, "  warnings: %d\n", warnCount)
+		}
+	} else if blockCount := report.Count(validate.SeverityBlock); blockCount > 0 {
+		fmt.Fprintf(builder, "FAIL: .lodetime validation found %d block(s), %d error(s), %d warning(s)\n", blockCount, errorCount, warnCount)
+	} else {
+		fmt.Fprintf(builder, "FAIL: .lodetime validation found %d error(s), %d warning(s)\n", errorCount, warnCount)
+	}
+
+	for _, finding := range report.Findings {
+		location := finding.Location()
+		if location != "" {
+			location += ": "
+		}
+		fmt.Fprintf(builder, "  - %s: %s%s [%s]\n", finding.Severity, location, finding.Message, finding.Rule)
+	}
+
+	return builder.String()
+}
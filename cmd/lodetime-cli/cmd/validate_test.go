@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/validate"
+)
+
+func TestRenderValidateHumanCountsBlocks(t *testing.T) {
+	report := &validate.Report{Findings: []validate.Finding{
+		{Rule: validate.RuleCircularDeps, Severity: validate.SeverityBlock, Message: "circular dependency: a → b → a"},
+		{Rule: validate.RuleBuildOrder, Severity: validate.SeverityWarn, Message: "component `c` is missing from build_order"},
+	}}
+
+	output := renderValidateHuman(report)
+	if !strings.HasPrefix(output, "FAIL: .lodetime validation found 1 block(s), 0 error(s), 1 warning(s)\n") {
+		t.Fatalf("unexpected summary:\n%s", output)
+	}
+
+	output, err := renderValidateJSON(report)
+	if err != nil {
+		t.Fatalf("renderValidateJSON error: %v", err)
+	}
+	if !strings.Contains(output, `"blocks": 1`) {
+		t.Fatalf("expected blocks in JSON:\n%s", output)
+	}
+}
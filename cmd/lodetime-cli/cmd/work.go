@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/client"
+	"github.com/lodetime/lodetime-cli/validate"
+	"github.com/spf13/cobra"
+)
+
+// sessionFile holds the offline work session, next to the project socket.
+const sessionFile = "session.json"
+
+// runtimeFiles are written under .lodetime/ by lode itself and are never
+// reported as changes.
+var runtimeFiles = []string{sessionFile, "lode.sock"}
+
+var (
+	workModes modeFlags
+	workLabel string
+	workJSON  bool
+)
+
+var beginWorkCmd = newWorkCommand(client.PhaseBeginWork, "Start a work session",
+	`Starts a work session. Changes are observed but not reported until the next
+checkpoint, so edits in progress do not raise warnings.`)
+
+var checkpointCmd = newWorkCommand(client.PhaseCheckpoint, "Validate the current snapshot",
+	`Validates the project as it is now and reports the files changed since
+begin-work (or since HEAD without a session), the components they touch and
+the validation findings. Exits non-zero when an error is found.`)
+
+var endWorkCmd = newWorkCommand(client.PhaseEndWork, "Finish a work session",
+	`Runs full validation and reports everything that changed during the
+session, then closes it. Exits non-zero when an error is found.`)
+
+// newWorkCommand builds one of the three checkpoint-protocol commands. They
+// ask the runtime first; without one (or before it supports the protocol)
+// the session is tracked in .lodetime/session.json and validated offline.
+func newWorkCommand(phase, short, long string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   phase,
+		Short: short,
+		Long:  long,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runWork(phase)
+		},
+	}
+	addModeFlags(cmd, &workModes)
+	cmd.Flags().StringVar(&workLabel, "label", "", "work session label")
+	cmd.Flags().BoolVar(&workJSON, "json", false, "output JSON only")
+	return cmd
+}
+
+func runWork(phase string) {
+	lodeDir := findLodeTimeRoot()
+	if lodeDir == "" {
+		fmt.Fprintln(os.Stderr, "Not in a LodeTime project (no .lodetime/ directory found)")
+		os.Exit(1)
+	}
+
+	mode, err := workModes.resolve()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var report *client.WorkReport
+	offline, err := queryRuntime(lodeDir, mode, phase, func(ctx context.Context, c *client.Client) error {
+		var err error
+		switch phase {
+		case client.PhaseBeginWork:
+			report, err = c.BeginWork(ctx, workLabel)
+		case client.PhaseCheckpoint:
+			report, err = c.Checkpoint(ctx, workLabel)
+		default:
+			report, err = c.EndWork(ctx, workLabel)
+		}
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Connected %s failed: %v\n", phase, err)
+		os.Exit(1)
+	}
+
+	if offline {
+		now := time.Now()
+		switch phase {
+		case client.PhaseBeginWork:
+			report, err = beginWorkOffline(lodeDir, workLabel, now)
+		case client.PhaseCheckpoint:
+			report, err = checkpointOffline(lodeDir, workLabel, now)
+		default:
+			report, err = endWorkOffline(lodeDir, workLabel, now)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if workJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to render JSON:", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(renderWorkHuman(report, offline))
+	}
+
+	if validate.Severity(report.Status).AtLeast(validate.SeverityError) {
+		os.Exit(1)
+	}
+}
+
+// workSession is the offline session state between begin-work and end-work.
+type workSession struct {
+	Label       string    `json:"label,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	Base        string    `json:"base,omitempty"`
+	Checkpoints int       `json:"checkpoints"`
+}
+
+func readSession(lodeDir string) (*workSession, error) {
+	data, err := os.ReadFile(filepath.Join(lodeDir, sessionFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session workSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("read %s: %w", sessionFile, err)
+	}
+	return &session, nil
+}
+
+func writeSession(lodeDir string, session *workSession) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(lodeDir, sessionFile), append(data, '\n'), 0o644)
+}
+
+// sessionLabel checks a --label against the open session.
+func sessionLabel(session *workSession, label string) (string, error) {
+	if session == nil || session.Label == "" {
+		return label, nil
+	}
+	if label != "" && label != session.Label {
+		return "", fmt.Errorf("work session %q is in progress (got --label %q)", session.Label, label)
+	}
+	return session.Label, nil
+}
+
+func beginWorkOffline(lodeDir, label string, now time.Time) (*client.WorkReport, error) {
+	session, err := readSession(lodeDir)
+	if err != nil {
+		return nil, err
+	}
+	if session != nil {
+		return nil, fmt.Errorf("work session %q already started at %s; run 'lode end-work' first",
+			session.Label, session.StartedAt.Format(time.RFC3339))
+	}
+
+	session = &workSession{Label: label, StartedAt: now}
+	if head, err := gitOutput(filepath.Dir(lodeDir), "rev-parse", "HEAD"); err == nil {
+		session.Base = strings.TrimSpace(head)
+	}
+	if err := writeSession(lodeDir, session); err != nil {
+		return nil, err
+	}
+
+	return &client.WorkReport{
+		Phase:     client.PhaseBeginWork,
+		Label:     label,
+		StartedAt: now.Format(time.RFC3339),
+		Base:      session.Base,
+		At:        now.Format(time.RFC3339),
+		Status:    "observing",
+	}, nil
+}
+
+func checkpointOffline(lodeDir, label string, now time.Time) (*client.WorkReport, error) {
+	session, err := readSession(lodeDir)
+	if err != nil {
+		return nil, err
+	}
+	if label, err = sessionLabel(session, label); err != nil {
+		return nil, err
+	}
+
+	if session != nil {
+		session.Checkpoints++
+		if err := writeSession(lodeDir, session); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshotReport(lodeDir, client.PhaseCheckpoint, label, session, now)
+}
+
+func endWorkOffline(lodeDir, label string, now time.Time) (*client.WorkReport, error) {
+	session, err := readSession(lodeDir)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, errors.New("no work session in progress; run 'lode begin-work' first")
+	}
+	if label, err = sessionLabel(session, label); err != nil {
+		return nil, err
+	}
+
+	report, err := snapshotReport(lodeDir, client.PhaseEndWork, label, session, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(filepath.Join(lodeDir, sessionFile)); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// snapshotReport validates .lodetime/ and collects the files changed since
+// the session base (HEAD without a session), including untracked files.
+func snapshotReport(lodeDir, phase, label string, session *workSession, now time.Time) (*client.WorkReport, error) {
+	projectRoot := filepath.Dir(lodeDir)
+	report := &client.WorkReport{
+		Phase:  phase,
+		Label:  label,
+		At:     now.Format(time.RFC3339),
+		Status: "ok",
+	}
+
+	base := "HEAD"
+	if session != nil {
+		report.StartedAt = session.StartedAt.Format(time.RFC3339)
+		report.Checkpoints = session.Checkpoints
+		if session.Base != "" {
+			base = session.Base
+			report.Base = session.Base
+		}
+	}
+
+	validation := validate.Dir(lodeDir)
+	for _, finding := range validation.Findings {
+		report.Findings = append(report.Findings, client.Finding{
+			Rule:     finding.Rule,
+			Severity: string(finding.Severity),
+			File:     finding.File,
+			Line:     finding.Line,
+			Message:  finding.Message,
+		})
+	}
+	report.Status = workStatus(validation)
+
+	paths, err := gitDiffFiles(projectRoot, base)
+	if err != nil {
+		// Without git there is nothing to diff; a recorded base means git
+		// was available, so losing it is an error.
+		if report.Base != "" {
+			return nil, err
+		}
+		return report, nil
+	}
+	untracked, err := gitUntrackedFiles(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []client.Component
+	if validation.Project != nil {
+		components = clientComponents(validation.Project.Components)
+	}
+
+	ignored := map[string]bool{}
+	for _, name := range runtimeFiles {
+		ignored[filepath.ToSlash(filepath.Join(filepath.Base(lodeDir), name))] = true
+	}
+
+	changed := []string{}
+	for _, path := range uniqueSorted(append(paths, untracked...)) {
+		if ignored[path] {
+			continue
+		}
+		id, _ := componentForPath(components, path)
+		report.Changes = append(report.Changes, client.Change{Path: path, Component: id})
+		if id != "" {
+			changed = append(changed, id)
+		}
+	}
+	if len(changed) > 0 {
+		report.Affected = affectedComponents(components, uniqueSorted(changed))
+	}
+
+	return report, nil
+}
+
+// workStatus is the most severe finding, or "ok".
+func workStatus(report *validate.Report) string {
+	if worst := report.Worst(); worst != "" {
+		return string(worst)
+	}
+	return "ok"
+}
+
+func renderWorkHuman(report *client.WorkReport, offline bool) string {
+	builder := &strings.Builder{}
+
+	mode := modeConnected
+	if offline {
+		mode = modeOffline
+	}
+
+	switch report.Phase {
+	case client.PhaseBeginWork:
+		fmt.Fprintln(builder, "Work Session Started")
+	case client.PhaseEndWork:
+		fmt.Fprintln(builder, "Work Session Ended")
+	default:
+		fmt.Fprintln(builder, "Checkpoint")
+	}
+	fmt.Fprintf(builder, "Mode: %s\n", mode)
+	if report.Label != "" {
+		fmt.Fprintf(builder, "Label: %s\n", report.Label)
+	}
+	if report.StartedAt != "" {
+		fmt.Fprintf(builder, "Started: %s\n", report.StartedAt)
+	}
+	if report.Base != "" {
+		fmt.Fprintf(builder, "Base: %s\n", shortRev(report.Base))
+	}
+
+	if report.Phase == client.PhaseBeginWork {
+		fmt.Fprintln(builder)
+		fmt.Fprintln(builder, "Observing changes; run 'lode checkpoint' to validate or 'lode end-work' to finish.")
+		return builder.String()
+	}
+
+	if report.Phase == client.PhaseEndWork {
+		if started, err := time.Parse(time.RFC3339, report.StartedAt); err == nil {
+			if at, err := time.Parse(time.RFC3339, report.At); err == nil {
+				fmt.Fprintf(builder, "Duration: %s\n", at.Sub(started).Round(time.Second))
+			}
+		}
+		fmt.Fprintf(builder, "Checkpoints: %d\n", report.Checkpoints)
+	}
+	fmt.Fprintf(builder, "Status: %s\n", report.Status)
+
+	fmt.Fprintln(builder)
+	fmt.Fprintf(builder, "Changes (%d)\n", len(report.Changes))
+	for _, change := range report.Changes {
+		if change.Component != "" {
+			fmt.Fprintf(builder, "  %s → %s\n", change.Path, change.Component)
+		} else {
+			fmt.Fprintf(builder, "  %s\n", change.Path)
+		}
+	}
+	fmt.Fprintf(builder, "Affected: %s\n", formatList(report.Affected))
+
+	fmt.Fprintln(builder)
+	fmt.Fprintf(builder, "Findings (%d)\n", len(report.Findings))
+	for _, finding := range report.Findings {
+		location := finding.File
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+		}
+		if location != "" {
+			location += ": "
+		}
+		fmt.Fprintf(builder, "  - %s: %s%s [%s]\n", finding.Severity, location, finding.Message, finding.Rule)
+	}
+
+	return builder.String()
+}
+
+func shortRev(rev string) string {
+	if len(rev) > 12 {
+		return rev[:12]
+	}
+	return rev
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lodetime/lodetime-cli/client"
+)
+
+// writeWorkRepo creates a git repository with a committed .lodetime/ tree and
+// returns the .lodetime path.
+func writeWorkRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"components/config-loader.yaml": "id: config-loader\nstatus: implemented\nlocation: lib/config/\ndepends_on: []\n",
+		"components/graph-server.yaml":  "id: graph-server\nstatus: implemented\nlocation: lib/graph/\ndepends_on: [config-loader]\n",
+	})
+	root := filepath.Dir(lodeDir)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", args[0], err, output)
+		}
+	}
+	return lodeDir
+}
+
+func TestWorkSessionOffline(t *testing.T) {
+	lodeDir := writeWorkRepo(t)
+	root := filepath.Dir(lodeDir)
+	started := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	begin, err := beginWorkOffline(lodeDir, "refactor", started)
+	if err != nil {
+		t.Fatalf("begin-work error: %v", err)
+	}
+	if begin.Base == "" || begin.Status != "observing" {
+		t.Fatalf("unexpected begin report: %+v", begin)
+	}
+	if _, err := beginWorkOffline(lodeDir, "other", started); err == nil {
+		t.Fatalf("expected second begin-work to fail")
+	}
+
+	writeFiles(t, root, map[string]string{"lib/config/loader.ex": "defmodule Loader do\nend\n"})
+
+	checkpoint, err := checkpointOffline(lodeDir, "", started.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("checkpoint error: %v", err)
+	}
+	if checkpoint.Label != "refactor" || checkpoint.Checkpoints != 1 {
+		t.Fatalf("unexpected checkpoint report: %+v", checkpoint)
+	}
+	if len(checkpoint.Changes) == 0 {
+		t.Fatalf("expected changes, got none")
+	}
+	found := false
+	for _, change := range checkpoint.Changes {
+		if change.Path == "lib/config/loader.ex" {
+			found = change.Component == "config-loader"
+		}
+		if change.Path == ".lodetime/"+sessionFile {
+			t.Fatalf("session file reported as a change: %+v", checkpoint.Changes)
+		}
+	}
+	if !found {
+		t.Fatalf("expected loader.ex mapped to config-loader: %+v", checkpoint.Changes)
+	}
+	if strings.Join(checkpoint.Affected, ",") != "config-loader,graph-server" {
+		t.Fatalf("unexpected affected: %v", checkpoint.Affected)
+	}
+
+	if _, err := checkpointOffline(lodeDir, "other", started); err == nil {
+		t.Fatalf("expected mismatched label to fail")
+	}
+
+	end, err := endWorkOffline(lodeDir, "", started.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("end-work error: %v", err)
+	}
+	if end.Phase != client.PhaseEndWork || end.Checkpoints != 1 || len(end.Changes) == 0 {
+		t.Fatalf("unexpected end report: %+v", end)
+	}
+	if _, err := os.Stat(filepath.Join(lodeDir, sessionFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected session file removed, got %v", err)
+	}
+	if _, err := endWorkOffline(lodeDir, "", started); err == nil {
+		t.Fatalf("expected end-work without a session to fail")
+	}
+
+	output := renderWorkHuman(end, true)
+	for _, want := range []string{"Work Session Ended", "Label: refactor", "Duration: 1h0m0s", "Checkpoints: 1", "lib/config/loader.ex → config-loader"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestCheckpointWithoutSessionReportsFindings(t *testing.T) {
+	lodeDir := writeLodeFixture(t, map[string]string{
+		"components/graph-server.yaml": "id: graph-server\nstatus: implemented\ndepends_on: [missing]\n",
+	})
+
+	report, err := checkpointOffline(lodeDir, "", time.Now())
+	if err != nil {
+		t.Fatalf("checkpoint error: %v", err)
+	}
+	if report.Status != "error" || len(report.Findings) == 0 {
+		t.Fatalf("expected error findings, got %+v", report)
+	}
+}
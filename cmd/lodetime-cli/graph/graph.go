@@ -0,0 +1,285 @@
+// Package graph is the offline component dependency graph built from the
+// depends_on edges in .lodetime/components/*.yaml.
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/model"
+)
+
+// Graph is a directed graph with an edge from each component to the
+// components it depends on. Edges to unknown components are dropped; the
+// validator reports those separately.
+type Graph struct {
+	ids   []string
+	known map[string]bool
+	edges map[string][]string
+}
+
+// New builds the graph for components.
+func New(components []model.Component) *Graph {
+	g := &Graph{known: map[string]bool{}, edges: map[string][]string{}}
+	for _, component := range components {
+		if !g.known[component.ID] {
+			g.known[component.ID] = true
+			g.ids = append(g.ids, component.ID)
+		}
+	}
+	sort.Strings(g.ids)
+
+	for _, component := range components {
+		for _, dep := range component.DependsOn {
+			if g.known[dep] && !contains(g.edges[component.ID], dep) {
+				g.edges[component.ID] = append(g.edges[component.ID], dep)
+			}
+		}
+	}
+
+	return g
+}
+
+// IDs returns every component ID, sorted.
+func (g *Graph) IDs() []string {
+	return append([]string{}, g.ids...)
+}
+
+// Has reports whether id is a component in the graph.
+func (g *Graph) Has(id string) bool {
+	return g.known[id]
+}
+
+// DependsOn returns the direct dependencies of id in declaration order.
+func (g *Graph) DependsOn(id string) []string {
+	return append([]string{}, g.edges[id]...)
+}
+
+// Dependents returns the components that depend directly on id, sorted.
+func (g *Graph) Dependents(id string) []string {
+	dependents := []string{}
+	for _, from := range g.ids {
+		if contains(g.edges[from], id) {
+			dependents = append(dependents, from)
+		}
+	}
+	return dependents
+}
+
+// Closure returns id and everything it depends on, directly or indirectly,
+// sorted.
+func (g *Graph) Closure(id string) []string {
+	if !g.known[id] {
+		return []string{}
+	}
+
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.edges[current] {
+			if !seen[dep] {
+				seen[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	closure := make([]string, 0, len(seen))
+	for dep := range seen {
+		closure = append(closure, dep)
+	}
+	sort.Strings(closure)
+	return closure
+}
+
+// Order returns every component with dependencies before their dependents.
+// Among components that are ready at the same time, the one listed first in
+// preferred wins, so an already-valid build_order comes back unchanged;
+// unlisted components rank after listed ones, in ID order. Components on a
+// cycle cannot be ordered and are appended at the end.
+func (g *Graph) Order(preferred []string) []string {
+	rank := map[string]int{}
+	for i, id := range preferred {
+		if _, seen := rank[id]; !seen && g.known[id] {
+			rank[id] = i
+		}
+	}
+	for i, id := range g.ids {
+		if _, seen := rank[id]; !seen {
+			rank[id] = len(preferred) + i
+		}
+	}
+
+	pending := map[string]int{}
+	for _, id := range g.ids {
+		pending[id] = len(g.edges[id])
+	}
+
+	order := []string{}
+	for len(order) < len(g.ids) {
+		next := ""
+		for id, count := range pending {
+			if count == 0 && (next == "" || rank[id] < rank[next]) {
+				next = id
+			}
+		}
+		if next == "" {
+			break
+		}
+
+		order = append(order, next)
+		delete(pending, next)
+		for _, dependent := range g.Dependents(next) {
+			pending[dependent]--
+		}
+	}
+
+	blocked := []string{}
+	for id := range pending {
+		blocked = append(blocked, id)
+	}
+	sort.Slice(blocked, func(i, j int) bool {
+		return rank[blocked[i]] < rank[blocked[j]]
+	})
+	return append(order, blocked...)
+}
+
+// Cycle is a dependency cycle written as a closed path: the first ID is
+// repeated at the end, e.g. [a b c a].
+type Cycle []string
+
+func (c Cycle) String() string {
+	return strings.Join(c, " → ")
+}
+
+// Cycles returns one cycle for each group of mutually dependent components
+// (strongly connected component), including components that depend on
+// themselves. Each cycle starts at its smallest ID; cycles are sorted.
+func (g *Graph) Cycles() []Cycle {
+	cycles := []Cycle{}
+	for _, group := range g.stronglyConnected() {
+		if len(group) == 1 && !contains(g.edges[group[0]], group[0]) {
+			continue
+		}
+		cycles = append(cycles, g.cycleWithin(group))
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// cycleWithin walks from the smallest ID of a strongly connected group,
+// always taking the first edge that stays in the group, until it returns to a
+// visited node. The closed part of that walk is a cycle.
+func (g *Graph) cycleWithin(group []string) Cycle {
+	inGroup := map[string]bool{}
+	for _, id := range group {
+		inGroup[id] = true
+	}
+
+	start := group[0]
+	for _, id := range group {
+		if id < start {
+			start = id
+		}
+	}
+
+	path := []string{start}
+	position := map[string]int{start: 0}
+	current := start
+	for {
+		next := ""
+		for _, dep := range g.edges[current] {
+			if inGroup[dep] {
+				next = dep
+				break
+			}
+		}
+
+		if at, seen := position[next]; seen {
+			cycle := append(Cycle{}, path[at:]...)
+			return rotate(append(cycle, next))
+		}
+		position[next] = len(path)
+		path = append(path, next)
+		current = next
+	}
+}
+
+// rotate rewrites a closed path to start at its smallest ID.
+func rotate(cycle Cycle) Cycle {
+	open := cycle[:len(cycle)-1]
+	smallest := 0
+	for i, id := range open {
+		if id < open[smallest] {
+			smallest = i
+		}
+	}
+
+	rotated := append(Cycle{}, open[smallest:]...)
+	rotated = append(rotated, open[:smallest]...)
+	return append(rotated, rotated[0])
+}
+
+// stronglyConnected runs Tarjan's algorithm over the graph.
+func (g *Graph) stronglyConnected() [][]string {
+	index := 0
+	indices := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	groups := [][]string{}
+
+	var visit func(id string)
+	visit = func(id string) {
+		indices[id] = index
+		lowlink[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, dep := range g.edges[id] {
+			if _, seen := indices[dep]; !seen {
+				visit(dep)
+				lowlink[id] = min(lowlink[id], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[id] = min(lowlink[id], indices[dep])
+			}
+		}
+
+		if lowlink[id] == indices[id] {
+			group := []string{}
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				group = append(group, top)
+				if top == id {
+					break
+				}
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	for _, id := range g.ids {
+		if _, seen := indices[id]; !seen {
+			visit(id)
+		}
+	}
+
+	return groups
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lodetime/lodetime-cli/model"
+)
+
+func components(edges map[string][]string) []model.Component {
+	out := []model.Component{}
+	for id, deps := range edges {
+		out = append(out, model.Component{ID: id, DependsOn: deps})
+	}
+	return out
+}
+
+func cycleStrings(cycles []Cycle) string {
+	out := []string{}
+	for _, cycle := range cycles {
+		out = append(out, cycle.String())
+	}
+	return strings.Join(out, "; ")
+}
+
+func TestCyclesReportsClosedPaths(t *testing.T) {
+	g := New(components(map[string][]string{
+		"c":     {"a"},
+		"a":     {"b"},
+		"b":     {"c", "ghost"},
+		"self":  {"self"},
+		"leaf":  {},
+		"user":  {"a", "leaf"},
+		"ring2": {"ring1"},
+		"ring1": {"ring2"},
+	}))
+
+	got := cycleStrings(g.Cycles())
+	want := "a → b → c → a; ring1 → ring2 → ring1; self → self"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCyclesEmptyForDAG(t *testing.T) {
+	g := New(components(map[string][]string{
+		"a": {},
+		"b": {"a"},
+		"c": {"a", "b"},
+	}))
+
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Fatalf("expected no cycles, got %v", cycleStrings(cycles))
+	}
+	if got := strings.Join(g.Dependents("a"), ","); got != "b,c" {
+		t.Fatalf("expected dependents b,c, got %s", got)
+	}
+}
+
+func TestOrderKeepsPreferredOrderWhenValid(t *testing.T) {
+	g := New(components(map[string][]string{
+		"config": {},
+		"graph":  {"config"},
+		"socket": {"graph"},
+		"cli":    {},
+	}))
+
+	got := strings.Join(g.Order([]string{"config", "cli", "graph", "socket"}), ",")
+	if got != "config,cli,graph,socket" {
+		t.Fatalf("expected preferred order kept, got %s", got)
+	}
+
+	got = strings.Join(g.Order([]string{"socket", "graph", "ghost"}), ",")
+	if got != "cli,config,graph,socket" {
+		t.Fatalf("expected dependencies first and unlisted in ID order, got %s", got)
+	}
+}
+
+func TestOrderAppendsCycleMembers(t *testing.T) {
+	g := New(components(map[string][]string{
+		"a":    {"b"},
+		"b":    {"a"},
+		"base": {},
+	}))
+
+	if got := strings.Join(g.Order(nil), ","); got != "base,a,b" {
+		t.Fatalf("expected base,a,b, got %s", got)
+	}
+}
@@ -0,0 +1,271 @@
+package imports
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// The Elixir scanner is line based and best effort: it needs no BEAM, and it
+// reads only what names a module (alias, import, use, require, remote calls
+// and structs). Comments, strings and heredocs are skipped.
+
+const moduleName = `[A-Z][A-Za-z0-9_]*(?:\.[A-Z][A-Za-z0-9_]*)*`
+
+var (
+	defmodulePattern = regexp.MustCompile(`^(\s*)defmodule\s+(` + moduleName + `)\s*,?\s*do\b`)
+	directivePattern = regexp.MustCompile(`^\s*(alias|import|use|require)\s+((?:__MODULE__\.)?` + moduleName + `|__MODULE__)(\.\{([^}]*)\})?(.*)$`)
+	// A directive whose {...} group continues on the following lines.
+	groupOpenPattern = regexp.MustCompile(`^\s*(alias|import|use|require)\s+\S*\.\{[^}]*$`)
+	aliasAsPattern   = regexp.MustCompile(`\bas:\s*(` + moduleName + `)`)
+	// A struct (%Module{) or a module followed by .function.
+	referencePattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.:%])(?:%((?:__MODULE__\.)?` + moduleName + `)\{|((?:__MODULE__\.)?` + moduleName + `)\.[a-z_][A-Za-z0-9_]*[?!]?)`)
+)
+
+// elixirModule is a module definition found in the project.
+type elixirModule struct {
+	name string
+	file string
+	line int
+}
+
+func isElixirSource(name string) bool {
+	return strings.HasSuffix(name, ".ex")
+}
+
+// Elixir scans the .ex files under dirs (project-relative directories) for
+// the modules they reference. Modules defined anywhere in the project resolve
+// to their defining file; others (Elixir, OTP, dependencies) stay unresolved.
+func Elixir(projectRoot string, dirs []string) (*Result, error) {
+	defined, err := elixirModules(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Files: []string{}, Imports: []Import{}}
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		err := walkFiles(projectRoot, dir, isElixirSource, func(rel string) error {
+			// Nested locations would otherwise scan a file twice.
+			if seen[rel] {
+				return nil
+			}
+			seen[rel] = true
+
+			data, err := os.ReadFile(filepath.Join(projectRoot, rel))
+			if err != nil {
+				return err
+			}
+			result.Files = append(result.Files, rel)
+			for _, imported := range scanElixir(rel, string(data)) {
+				imported.Path = defined[imported.Name]
+				result.Imports = append(result.Imports, imported)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// elixirModules maps every module defined in the project's .ex files to the
+// file defining it.
+func elixirModules(projectRoot string) (map[string]string, error) {
+	defined := map[string]string{}
+	err := walkFiles(projectRoot, ".", isElixirSource, func(rel string) error {
+		data, err := os.ReadFile(filepath.Join(projectRoot, rel))
+		if err != nil {
+			return err
+		}
+		for _, module := range definedModules(rel, string(data)) {
+			if _, ok := defined[module.name]; !ok {
+				defined[module.name] = module.file
+			}
+		}
+		return nil
+	})
+	return defined, err
+}
+
+// elixirLines yields code lines with comments removed and string contents
+// blanked, skipping heredocs.
+func elixirLines(source string, fn func(number int, line string)) {
+	scanner := bufio.NewScanner(strings.NewReader(source))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	inHeredoc := ""
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if inHeredoc != "" {
+			if strings.Contains(line, inHeredoc) {
+				inHeredoc = ""
+			}
+			continue
+		}
+
+		for _, delimiter := range []string{`"""`, `'''`} {
+			if strings.Count(line, delimiter)%2 == 1 {
+				// Keep the code before the heredoc opens, e.g. @doc """.
+				line = line[:strings.Index(line, delimiter)]
+				inHeredoc = delimiter
+				break
+			}
+		}
+		fn(number, codeOnly(line))
+	}
+}
+
+// codeOnly cuts a trailing # comment and blanks string and charlist contents,
+// so module names in text are not mistaken for references.
+func codeOnly(line string) string {
+	out := []byte(line)
+	quote := byte(0)
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case quote != 0 && c == '\\':
+			out[i] = ' '
+			if i+1 < len(out) {
+				i++
+				out[i] = ' '
+			}
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			out[i] = ' '
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return string(out[:i])
+		}
+	}
+	return string(out)
+}
+
+// definedModules returns the modules a file defines. A defmodule indented
+// under another one is nested and gets the enclosing module's prefix.
+func definedModules(file, source string) []elixirModule {
+	type open struct {
+		indent int
+		name   string
+	}
+
+	modules := []elixirModule{}
+	stack := []open{}
+	elixirLines(source, func(number int, line string) {
+		match := defmodulePattern.FindStringSubmatch(line)
+		if match == nil {
+			return
+		}
+		indent := len(match[1])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		name := match[2]
+		if len(stack) > 0 {
+			name = stack[len(stack)-1].name + "." + name
+		}
+		stack = append(stack, open{indent: indent, name: name})
+		modules = append(modules, elixirModule{name: name, file: file, line: number})
+	})
+	return modules
+}
+
+// scanElixir returns the modules a file references, once per module at its
+// first line, with aliases and __MODULE__ expanded. References to the file's
+// own modules are dropped.
+func scanElixir(file, source string) []Import {
+	own := map[string]bool{}
+	defined := map[int]string{}
+	for _, module := range definedModules(file, source) {
+		own[module.name] = true
+		defined[module.line] = module.name
+	}
+
+	current := ""
+	aliases := map[string]string{}
+	expand := func(name string) string {
+		if rest, ok := strings.CutPrefix(name, "__MODULE__"); ok {
+			if current == "" {
+				return ""
+			}
+			return current + rest
+		}
+		head, rest, _ := strings.Cut(name, ".")
+		if full, ok := aliases[head]; ok {
+			if rest == "" {
+				return full
+			}
+			return full + "." + rest
+		}
+		return name
+	}
+
+	found := []Import{}
+	seen := map[string]bool{}
+	add := func(number int, name string) {
+		if name == "" || own[name] || seen[name] {
+			return
+		}
+		seen[name] = true
+		found = append(found, Import{File: file, Line: number, Name: name})
+	}
+
+	// A multi-line alias App.{ ... } is joined up to its closing brace and
+	// reported at its first line.
+	group, groupLine := "", 0
+	elixirLines(source, func(number int, line string) {
+		if group != "" {
+			group += " " + strings.TrimSpace(line)
+			if !strings.Contains(line, "}") {
+				return
+			}
+			line, number, group = group, groupLine, ""
+		} else if groupOpenPattern.MatchString(line) {
+			group, groupLine = line, number
+			return
+		}
+
+		// Best effort: __MODULE__ is the most recently opened module.
+		if name, ok := defined[number]; ok {
+			current = name
+			return
+		}
+
+		if match := directivePattern.FindStringSubmatch(line); match != nil {
+			base := expand(match[2])
+			names := []string{base}
+			if match[4] != "" {
+				names = names[:0]
+				for _, part := range strings.Split(match[4], ",") {
+					if part = strings.TrimSpace(part); part != "" && base != "" {
+						names = append(names, base+"."+part)
+					}
+				}
+			}
+
+			for _, name := range names {
+				add(number, name)
+				if match[1] != "alias" || name == "" {
+					continue
+				}
+				short := name[strings.LastIndex(name, ".")+1:]
+				if as := aliasAsPattern.FindStringSubmatch(match[5]); as != nil && match[4] == "" {
+					short = as[1]
+				}
+				aliases[short] = name
+			}
+			return
+		}
+
+		for _, match := range referencePattern.FindAllStringSubmatch(line, -1) {
+			add(number, expand(match[1]+match[2]))
+		}
+	})
+	return found
+}
@@ -0,0 +1,120 @@
+package imports
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanElixirReferences(t *testing.T) {
+	source := `defmodule LodeTime.Interface.Handler do
+  @moduledoc """
+  Talks to LodeTime.Docs.Only, which must not count.
+  """
+  use GenServer
+  alias LodeTime.Graph.Server
+  alias LodeTime.Config.{Loader, Model}
+  alias LodeTime.State.Store, as: Store2
+  import LodeTime.Util
+  require Logger
+
+  # LodeTime.Commented.out()
+  def run(path) do
+    Server.reload()
+    {:ok, config} = Loader.load(path)
+    _ = %Model.Config{}
+    _ = Store2.get(:x)
+    _ = LodeTime.Events.broadcast(:x, "LodeTime.InString.call()")
+    :ets.new(:table, [])
+    __MODULE__.Inner.helper()
+  end
+
+  defmodule Inner do
+    def helper, do: Enum.map([], & &1)
+  end
+end
+`
+
+	got := []string{}
+	for _, imported := range scanElixir("lib/handler.ex", source) {
+		got = append(got, imported.Name)
+	}
+	want := []string{
+		"GenServer",
+		"LodeTime.Graph.Server",
+		"LodeTime.Config.Loader",
+		"LodeTime.Config.Model",
+		"LodeTime.State.Store",
+		"LodeTime.Util",
+		"Logger",
+		"LodeTime.Config.Model.Config",
+		"LodeTime.Events",
+		"Enum",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected references:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestScanElixirMultiLineAliasGroup(t *testing.T) {
+	source := `defmodule App.Web do
+  alias App.{
+    Accounts,
+    Billing.Invoice, # billing
+  }
+  import App.Util
+
+  def run, do: Invoice.total(Accounts.current())
+end
+`
+
+	got := []string{}
+	for _, imported := range scanElixir("lib/web.ex", source) {
+		got = append(got, imported.Name)
+		if imported.Name == "App.Billing.Invoice" && imported.Line != 2 {
+			t.Fatalf("expected the group at its first line, got %d", imported.Line)
+		}
+	}
+	want := "App.Accounts,App.Billing.Invoice,App.Util"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected references: %v", got)
+	}
+}
+
+func TestDefinedModulesNest(t *testing.T) {
+	source := "defmodule A.B do\n  defmodule C do\n  end\nend\ndefmodule D do\nend\n"
+
+	got := []string{}
+	for _, module := range definedModules("lib/a.ex", source) {
+		got = append(got, module.name)
+	}
+	if strings.Join(got, ",") != "A.B,A.B.C,D" {
+		t.Fatalf("unexpected modules: %v", got)
+	}
+}
+
+func TestElixirResolvesProjectModules(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"lib/app/graph/server.ex": "defmodule App.Graph.Server do\n  def reload, do: :ok\nend\n",
+		"lib/app/cli/handler.ex":  "defmodule App.Cli.Handler do\n  alias App.Graph.Server\n  def go, do: Server.reload() && Jason.encode!(%{})\nend\n",
+		"deps/jason/lib/jason.ex": "defmodule Jason do\nend\n",
+	})
+
+	result, err := Elixir(root, []string{"lib/app/cli/"})
+	if err != nil {
+		t.Fatalf("Elixir error: %v", err)
+	}
+	if strings.Join(result.Files, ",") != "lib/app/cli/handler.ex" {
+		t.Fatalf("unexpected files: %v", result.Files)
+	}
+
+	paths := map[string]string{}
+	for _, imported := range result.Imports {
+		paths[imported.Name] = imported.Path
+	}
+	if paths["App.Graph.Server"] != "lib/app/graph/server.ex" {
+		t.Fatalf("expected server resolved, got %+v", result.Imports)
+	}
+	if path, ok := paths["Jason"]; !ok || path != "" {
+		t.Fatalf("expected Jason unresolved (deps/ is skipped), got %+v", result.Imports)
+	}
+}
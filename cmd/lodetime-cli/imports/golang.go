@@ -0,0 +1,122 @@
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goModule is a go.mod found in the project.
+type goModule struct {
+	path string // module path, e.g. github.com/lodetime/lodetime-cli
+	dir  string // project-relative directory of go.mod
+}
+
+// Go parses the imports of every non-test .go file under dirs (project-relative
+// directories). Imports of packages in a module that lives in the project
+// resolve to that package's directory.
+func Go(projectRoot string, dirs []string) (*Result, error) {
+	modules, err := goModules(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	isSource := func(name string) bool {
+		return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+	}
+
+	result := &Result{Files: []string{}, Imports: []Import{}}
+	seen := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		err := walkFiles(projectRoot, dir, isSource, func(rel string) error {
+			// Nested locations would otherwise scan a file twice.
+			if seen[rel] {
+				return nil
+			}
+			seen[rel] = true
+
+			file, err := parser.ParseFile(fset, filepath.Join(projectRoot, rel), nil, parser.ImportsOnly)
+			if err != nil {
+				return err
+			}
+			result.Files = append(result.Files, rel)
+			for _, spec := range file.Imports {
+				name, err := strconv.Unquote(spec.Path.Value)
+				if err != nil {
+					continue
+				}
+				result.Imports = append(result.Imports, Import{
+					File: rel,
+					Line: fset.Position(spec.Pos()).Line,
+					Name: name,
+					Path: resolveGoImport(modules, name),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// goModules finds every go.mod in the project, longest module path first so
+// nested modules win over their parents.
+func goModules(projectRoot string) ([]goModule, error) {
+	modules := []goModule{}
+	err := walkFiles(projectRoot, ".", func(name string) bool { return name == "go.mod" }, func(rel string) error {
+		data, err := os.ReadFile(filepath.Join(projectRoot, rel))
+		if err != nil {
+			return err
+		}
+		if module := modulePath(data); module != "" {
+			modules = append(modules, goModule{path: module, dir: path.Dir(rel)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return len(modules[i].path) > len(modules[j].path)
+	})
+	return modules, nil
+}
+
+// modulePath reads the module directive of a go.mod file.
+func modulePath(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		rest, ok := strings.CutPrefix(line, "module")
+		if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		rest = strings.TrimSpace(rest)
+		if unquoted, err := strconv.Unquote(rest); err == nil {
+			return unquoted
+		}
+		return strings.Fields(rest)[0]
+	}
+	return ""
+}
+
+func resolveGoImport(modules []goModule, name string) string {
+	for _, module := range modules {
+		if name != module.path && !strings.HasPrefix(name, module.path+"/") {
+			continue
+		}
+		return path.Join(module.dir, strings.TrimPrefix(name, module.path))
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package imports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	return root
+}
+
+func TestGoResolvesProjectImports(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"tools/go.mod":              "module example.com/tools\n\ngo 1.22\n",
+		"tools/main.go":             "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/tools/store\"\n)\n",
+		"tools/store/store.go":      "package store\n\nimport \"example.com/other/lib\"\n",
+		"tools/store/store_test.go": "package store\n\nimport \"example.com/tools\"\n",
+		"tools/vendor/x/x.go":       "package x\n\nimport \"example.com/tools/store\"\n",
+	})
+
+	result, err := Go(root, []string{"tools/", "tools/store/", "missing/"})
+	if err != nil {
+		t.Fatalf("Go error: %v", err)
+	}
+
+	if len(result.Files) != 2 {
+		t.Fatalf("expected main.go and store.go once each, got %v", result.Files)
+	}
+
+	got := map[string]Import{}
+	for _, imported := range result.Imports {
+		got[imported.Name] = imported
+	}
+	if len(got) != 3 {
+		t.Fatalf("unexpected imports: %+v", result.Imports)
+	}
+	if store := got["example.com/tools/store"]; store.Path != "tools/store" || store.File != "tools/main.go" || store.Line != 6 {
+		t.Fatalf("unexpected store import: %+v", store)
+	}
+	if got["fmt"].Path != "" || got["example.com/other/lib"].Path != "" {
+		t.Fatalf("expected external imports unresolved: %+v", result.Imports)
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	for data, want := range map[string]string{
+		"module example.com/a\n":                 "example.com/a",
+		"// comment\nmodule \"example.com/b\"\n": "example.com/b",
+		"modules x\n":                            "",
+	} {
+		if got := modulePath([]byte(data)); got != want {
+			t.Fatalf("modulePath(%q) = %q, want %q", data, got, want)
+		}
+	}
+}
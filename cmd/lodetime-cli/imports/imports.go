@@ -0,0 +1,72 @@
+// Package imports extracts source-level dependencies from a project so they
+// can be compared with the depends_on edges declared in .lodetime/. Each
+// scanner resolves what a file imports to the project path that defines it;
+// mapping paths to components is left to the caller.
+package imports
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// Import is one dependency of a source file.
+type Import struct {
+	// File is the importing file, relative to the project root.
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Name is the import as written: a Go import path or an Elixir module.
+	Name string `json:"name"`
+	// Path is the project-relative file or directory defining Name, or ""
+	// when it lives outside the project (standard library, dependencies).
+	Path string `json:"path,omitempty"`
+}
+
+// Result is what a scanner found: every file it read and their imports.
+type Result struct {
+	Files   []string `json:"files"`
+	Imports []Import `json:"imports"`
+}
+
+// skipDir reports directories never scanned: hidden and underscore-prefixed
+// directories, dependencies, build output and Go test data.
+func skipDir(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+	switch name {
+	case "vendor", "testdata", "node_modules", "deps":
+		return true
+	}
+	return false
+}
+
+// walkFiles calls fn with the project-relative path of every file under root
+// (a project-relative directory) whose name satisfies match.
+func walkFiles(projectRoot, root string, match func(name string) bool, fn func(rel string) error) error {
+	start := filepath.Join(projectRoot, filepath.FromSlash(root))
+	return filepath.WalkDir(start, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == start {
+				// A location that does not exist yet has nothing to scan.
+				return fs.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if path != start && skipDir(entry.Name()) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !match(entry.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(projectRoot, path)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel))
+	})
+}
@@ -0,0 +1,243 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Project is a loaded .lodetime/ directory.
+type Project struct {
+	// Dir is the .lodetime/ directory and Root the project root containing it.
+	Dir  string
+	Root string
+
+	Config     Config
+	Components []Component // sorted by ID
+	Contracts  []Contract  // sorted by ID
+
+	// Warnings are problems that do not prevent loading, such as unknown keys.
+	Warnings []Problem
+}
+
+// LoadError reports every problem that prevented loading.
+type LoadError struct {
+	Problems []Problem
+}
+
+func (e *LoadError) Error() string {
+	switch len(e.Problems) {
+	case 0:
+		return "load failed"
+	case 1:
+		return e.Problems[0].String()
+	}
+	return fmt.Sprintf("%s (and %d more problems)", e.Problems[0], len(e.Problems)-1)
+}
+
+// Load reads config.yaml, components/*.yaml and contracts/*.yaml from lodeDir.
+// Unreadable or malformed files are collected into a *LoadError; unknown keys
+// become warnings. A record without an id takes its file name.
+func Load(lodeDir string) (*Project, error) {
+	project := &Project{Dir: lodeDir, Root: filepath.Dir(lodeDir)}
+	var problems []Problem
+
+	warnings, problem := decodeFile(lodeDir, "config.yaml", &project.Config, &project.Config.Source)
+	project.Warnings = append(project.Warnings, warnings...)
+	if problem != nil {
+		problems = append(problems, *problem)
+	}
+
+	files, problem := listYAML(lodeDir, "components")
+	if problem != nil {
+		problems = append(problems, *problem)
+	}
+	for _, rel := range files {
+		var component Component
+		warnings, problem := decodeFile(lodeDir, rel, &component, &component.Source)
+		project.Warnings = append(project.Warnings, warnings...)
+		if problem != nil {
+			problems = append(problems, *problem)
+			continue
+		}
+		if component.ID == "" {
+			component.ID = idFromFile(rel)
+		}
+		project.Components = append(project.Components, component)
+	}
+
+	files, problem = listYAML(lodeDir, "contracts")
+	if problem != nil {
+		problems = append(problems, *problem)
+	}
+	for _, rel := range files {
+		var contract Contract
+		warnings, problem := decodeFile(lodeDir, rel, &contract, &contract.Source)
+		project.Warnings = append(project.Warnings, warnings...)
+		if problem != nil {
+			problems = append(problems, *problem)
+			continue
+		}
+		if contract.ID == "" {
+			contract.ID = idFromFile(rel)
+		}
+		project.Contracts = append(project.Contracts, contract)
+	}
+
+	if len(problems) > 0 {
+		return nil, &LoadError{Problems: problems}
+	}
+
+	sort.SliceStable(project.Components, func(i, j int) bool {
+		return project.Components[i].ID < project.Components[j].ID
+	})
+	sort.SliceStable(project.Contracts, func(i, j int) bool {
+		return project.Contracts[i].ID < project.Contracts[j].ID
+	})
+
+	return project, nil
+}
+
+// ReadConfig reads a single config file, such as the user-level
+// ~/.config/lode/config.yaml. Unknown keys are ignored.
+func ReadConfig(path string) (*Config, error) {
+	var config Config
+	_, problem := decodeFile(filepath.Dir(path), filepath.Base(path), &config, &config.Source)
+	if problem != nil {
+		return nil, &LoadError{Problems: []Problem{*problem}}
+	}
+	return &config, nil
+}
+
+// Component returns the component with id.
+func (p *Project) Component(id string) (*Component, bool) {
+	for i := range p.Components {
+		if p.Components[i].ID == id {
+			return &p.Components[i], true
+		}
+	}
+	return nil, false
+}
+
+// Contract returns the contract with id.
+func (p *Project) Contract(id string) (*Contract, bool) {
+	for i := range p.Contracts {
+		if p.Contracts[i].ID == id {
+			return &p.Contracts[i], true
+		}
+	}
+	return nil, false
+}
+
+func listYAML(lodeDir, subdir string) ([]string, *Problem) {
+	entries, err := os.ReadDir(filepath.Join(lodeDir, subdir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &Problem{File: subdir + "/", Message: "missing directory"}
+		}
+		return nil, &Problem{File: subdir + "/", Message: err.Error()}
+	}
+
+	files := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		files = append(files, subdir+"/"+entry.Name())
+	}
+	return files, nil
+}
+
+func idFromFile(rel string) string {
+	return strings.TrimSuffix(filepath.Base(rel), ".yaml")
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+): (.*)`)
+var unknownFieldPattern = regexp.MustCompile(`field (\S+) not found in type`)
+
+// decodeFile decodes dir/rel into out and records key lines in source. It
+// returns unknown-key warnings and, if the file cannot be used, a problem.
+func decodeFile(dir, rel string, out any, source *Source) ([]Problem, *Problem) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &Problem{File: rel, Message: "missing file"}
+		}
+		return nil, &Problem{File: rel, Message: err.Error()}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		problem := yamlProblem(rel, err)
+		return nil, &problem
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, &Problem{File: rel, Line: 1, Message: "not a YAML mapping"}
+	}
+	root := doc.Content[0]
+
+	if err := root.Decode(out); err != nil {
+		problem := yamlProblem(rel, err)
+		return nil, &problem
+	}
+
+	source.File = rel
+	source.lines = map[string]int{}
+	source.present = map[string]bool{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key := root.Content[i]
+		source.lines[key.Value] = key.Line
+		source.present[key.Value] = true
+	}
+
+	return unknownKeys(rel, data, out), nil
+}
+
+// unknownKeys re-decodes data strictly into a fresh value of out's type and
+// reports each unknown key, which is usually a typo.
+func unknownKeys(rel string, data []byte, out any) []Problem {
+	strict := reflect.New(reflect.TypeOf(out).Elem()).Interface()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var typeErr *yaml.TypeError
+	if err := decoder.Decode(strict); !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	warnings := []Problem{}
+	for _, message := range typeErr.Errors {
+		match := unknownFieldPattern.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		problem := yamlProblem(rel, errors.New(message))
+		problem.Message = fmt.Sprintf("unknown key `%s`", match[1])
+		warnings = append(warnings, problem)
+	}
+	return warnings
+}
+
+// yamlProblem extracts the line number from a yaml.v3 error message.
+func yamlProblem(rel string, err error) Problem {
+	var typeErr *yaml.TypeError
+	message := err.Error()
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+
+	if match := yamlLinePattern.FindStringSubmatch(message); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return Problem{File: rel, Line: line, Message: match[2]}
+	}
+	return Problem{File: rel, Message: strings.TrimPrefix(message, "yaml: ")}
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	lodeDir := filepath.Join(t.TempDir(), ".lodetime")
+	for _, dir := range []string{"components", "contracts"} {
+		if err := os.MkdirAll(filepath.Join(lodeDir, dir), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(lodeDir, rel), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	return lodeDir
+}
+
+func TestLoadProject(t *testing.T) {
+	lodeDir := writeProject(t, map[string]string{
+		"config.yaml": `project: demo
+schema_version: 1
+zones:
+  core:
+    paths: [lib/]
+    tracking: full
+build_order: [b, a]
+runtime:
+  endpoint: unix://
+triggers:
+  git:
+    on_stage: true
+`,
+		"components/a.yaml":  "id: a\nname: A\nstatus: planned\nlocation: lib/a/\ndepends_on: [b]\n",
+		"components/b.yaml":  "name: B\nstatus: implemented\nlocation: lib/b/\ndepends_on: []\n",
+		"contracts/api.yaml": "id: api\nname: API\ndescription: d\noperations:\n  - {name: get}\n",
+	})
+
+	project, err := Load(lodeDir)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if project.Config.Project != "demo" || project.Config.Zones["core"].Tracking != "full" {
+		t.Fatalf("unexpected config: %+v", project.Config)
+	}
+	if project.Config.EndpointSetting() != "unix://" || !project.Config.Triggers.Git.StageChecks() {
+		t.Fatalf("unexpected runtime/triggers: %+v", project.Config)
+	}
+	if len(project.Components) != 2 || project.Components[1].ID != "b" {
+		t.Fatalf("expected id from file name, got %+v", project.Components)
+	}
+
+	a, ok := project.Component("a")
+	if !ok || a.DependsOn[0] != "b" || a.File != "components/a.yaml" || a.Line("depends_on") != 5 {
+		t.Fatalf("unexpected component a: %+v (depends_on line %d)", a, a.Line("depends_on"))
+	}
+	b, _ := project.Component("b")
+	if !b.Has("depends_on") || b.Has("tests") {
+		t.Fatalf("expected depends_on present and tests absent on b")
+	}
+
+	contract, ok := project.Contract("api")
+	if !ok || contract.Spec["operations"] == nil {
+		t.Fatalf("expected contract body kept in Spec, got %+v", contract)
+	}
+	if len(project.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", project.Warnings)
+	}
+}
+
+func TestStageChecksDefaultToEnabled(t *testing.T) {
+	for config, want := range map[string]bool{
+		"project: demo\n": true,
+		"project: demo\ntriggers:\n  git:\n    on_commit: true\n": true,
+		"project: demo\ntriggers:\n  git:\n    on_stage: false\n": false,
+	} {
+		project, err := Load(writeProject(t, map[string]string{"config.yaml": config}))
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		if got := project.Config.Triggers.Git.StageChecks(); got != want {
+			t.Fatalf("StageChecks() = %v for %q, want %v", got, config, want)
+		}
+	}
+}
+
+func TestLoadReportsUnknownKeysWithLines(t *testing.T) {
+	lodeDir := writeProject(t, map[string]string{
+		"config.yaml":       "project: demo\nzones:\n  core:\n    path: [lib/]\n",
+		"components/a.yaml": "id: a\nstatus: planned\ndependson: [b]\n",
+	})
+
+	project, err := Load(lodeDir)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	var got []string
+	for _, warning := range project.Warnings {
+		got = append(got, warning.String())
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{"config.yaml:4: unknown key `path`", "components/a.yaml:3: unknown key `dependson`"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected warning %q, got:\n%s", want, joined)
+		}
+	}
+}
+
+func TestLoadCollectsErrorsWithLines(t *testing.T) {
+	lodeDir := writeProject(t, map[string]string{
+		"config.yaml":       "project: demo\n",
+		"components/a.yaml": "id: a\ndepends_on: [b\n",
+		"components/b.yaml": "id: b\nschema_version: one\n",
+	})
+
+	_, err := Load(lodeDir)
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("expected LoadError, got %v", err)
+	}
+	if len(loadErr.Problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", loadErr.Problems)
+	}
+	for _, problem := range loadErr.Problems {
+		if problem.Line == 0 {
+			t.Fatalf("expected line number in %s", problem)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package model is the typed form of a project's .lodetime/ directory:
+// config.yaml, components/*.yaml and contracts/*.yaml. It mirrors
+// LodeTime.Config.Model on the runtime side so offline commands can share one
+// parsed graph.
+package model
+
+import "fmt"
+
+// Component statuses, in lifecycle order.
+const (
+	StatusPlanned      = "planned"
+	StatusImplementing = "implementing"
+	StatusImplemented  = "implemented"
+	StatusDeprecated   = "deprecated"
+)
+
+// Statuses lists the valid component statuses in lifecycle order.
+var Statuses = []string{StatusPlanned, StatusImplementing, StatusImplemented, StatusDeprecated}
+
+// Config is .lodetime/config.yaml.
+type Config struct {
+	Project       string          `yaml:"project"`
+	Version       string          `yaml:"version"`
+	SchemaVersion int             `yaml:"schema_version"`
+	Description   string          `yaml:"description"`
+	CurrentPhase  int             `yaml:"current_phase"`
+	BootstrapMode bool            `yaml:"bootstrap_mode"`
+	ActiveProfile string          `yaml:"active_profile"`
+	Languages     []string        `yaml:"languages"`
+	Zones         map[string]Zone `yaml:"zones"`
+	BuildOrder    []string        `yaml:"build_order"`
+	Rules         []Rule          `yaml:"rules"`
+	Triggers      Triggers        `yaml:"triggers"`
+	Runtime       Runtime         `yaml:"runtime"`
+	Drift         Drift           `yaml:"drift"`
+
+	// Flat spellings of runtime.endpoint and runtime.engine, still accepted.
+	LegacyRuntimeEndpoint string `yaml:"runtime_endpoint"`
+	LegacyEndpoint        string `yaml:"endpoint"`
+	LegacyRuntimeEngine   string `yaml:"runtime_engine"`
+	LegacyEngine          string `yaml:"engine"`
+
+	Source `yaml:"-"`
+}
+
+// Zone groups paths that share tracking and rules.
+type Zone struct {
+	Paths    []string `yaml:"paths"`
+	Tracking string   `yaml:"tracking"`
+	Rules    []string `yaml:"rules"`
+	OnImport string   `yaml:"on_import"`
+}
+
+// Rule is an architectural rule with its severity.
+type Rule struct {
+	ID       string `yaml:"id"`
+	Severity string `yaml:"severity"`
+}
+
+// Triggers configures what makes the runtime re-check the project.
+type Triggers struct {
+	FileSystem FileSystemTrigger `yaml:"file_system"`
+	Git        GitTrigger        `yaml:"git"`
+}
+
+// FileSystemTrigger configures the file watcher.
+type FileSystemTrigger struct {
+	Enabled    bool     `yaml:"enabled"`
+	DebounceMS int      `yaml:"debounce_ms"`
+	Ignore     []string `yaml:"ignore"`
+}
+
+// GitTrigger configures git-driven checks. The hook settings decide which
+// hooks lode hooks install writes.
+type GitTrigger struct {
+	// OnStage is nil when on_stage is not set; see StageChecks.
+	OnStage       *bool `yaml:"on_stage"`
+	OnCommit      bool  `yaml:"on_commit"`
+	PreCommitHook bool  `yaml:"pre_commit_hook"`
+	PrePushHook   bool  `yaml:"pre_push_hook"`
+}
+
+// StageChecks reports whether lode check --staged runs. Only an explicit
+// on_stage: false turns it off.
+func (g GitTrigger) StageChecks() bool {
+	return g.OnStage == nil || *g.OnStage
+}
+
+// Drift configures lode drift. The thresholds count commits touching a
+// component's location since its YAML last changed; 0 uses the default.
+type Drift struct {
+	WarnAfter  int `yaml:"warn_after"`
+	ErrorAfter int `yaml:"error_after"`
+}
+
+// Runtime configures how the CLI reaches or starts the runtime.
+type Runtime struct {
+	Endpoint string `yaml:"endpoint"`
+	Engine   string `yaml:"engine"`
+}
+
+// EndpointSetting returns the configured runtime endpoint, honouring the
+// legacy flat keys.
+func (c *Config) EndpointSetting() string {
+	for _, value := range []string{c.Runtime.Endpoint, c.LegacyRuntimeEndpoint, c.LegacyEndpoint} {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// EngineSetting returns the configured runtime engine, honouring the legacy
+// flat keys.
+func (c *Config) EngineSetting() string {
+	for _, value := range []string{c.Runtime.Engine, c.LegacyRuntimeEngine, c.LegacyEngine} {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Component is a .lodetime/components/*.yaml file.
+type Component struct {
+	ID                  string       `yaml:"id"`
+	SchemaVersion       int          `yaml:"schema_version"`
+	Name                string       `yaml:"name"`
+	Status              string       `yaml:"status"`
+	Description         string       `yaml:"description"`
+	Location            string       `yaml:"location"`
+	Language            string       `yaml:"language"`
+	DependsOn           []string     `yaml:"depends_on"`
+	ImplementsContracts []string     `yaml:"implements_contracts"`
+	Tests               []string     `yaml:"tests"`
+	Constraints         *Constraints `yaml:"constraints"`
+
+	Source `yaml:"-"`
+}
+
+// Constraints are patterns a component must follow or avoid.
+type Constraints struct {
+	Require []string `yaml:"require"`
+	Forbid  []string `yaml:"forbid"`
+}
+
+// Contract is a .lodetime/contracts/*.yaml file. Contract bodies
+// (operations, tools, commands, ...) vary by kind and are kept in Spec.
+type Contract struct {
+	ID            string         `yaml:"id"`
+	SchemaVersion int            `yaml:"schema_version"`
+	Name          string         `yaml:"name"`
+	Version       string         `yaml:"version"`
+	Description   string         `yaml:"description"`
+	Spec          map[string]any `yaml:",inline"`
+
+	Source `yaml:"-"`
+}
+
+// Source locates a loaded record on disk.
+type Source struct {
+	// File is relative to the .lodetime/ directory, e.g. "components/cli.yaml".
+	File  string
+	lines map[string]int
+	// present records which top-level keys were written in the file.
+	present map[string]bool
+}
+
+// Line returns the line of a top-level key, or the first line of the file
+// when the key is absent.
+func (s Source) Line(key string) int {
+	if line, ok := s.lines[key]; ok {
+		return line
+	}
+	return 1
+}
+
+// Has reports whether a top-level key is present in the file, which tells an
+// explicit empty value apart from a missing one.
+func (s Source) Has(key string) bool {
+	return s.present[key]
+}
+
+// Problem is a located issue in a .lodetime/ file.
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
@@ -0,0 +1,23 @@
+// Package terminal puts a terminal into raw mode and reads its size for the
+// full-screen commands. It covers Linux and the BSDs (including macOS); other
+// platforms report ErrUnsupported.
+package terminal
+
+import "errors"
+
+// ErrUnsupported is returned on platforms without termios support.
+var ErrUnsupported = errors.New("terminal: raw mode is not supported on this platform")
+
+// ANSI sequences used by the full-screen commands.
+const (
+	EnterAltScreen = "\x1b[?1049h"
+	ExitAltScreen  = "\x1b[?1049l"
+	HideCursor     = "\x1b[?25l"
+	ShowCursor     = "\x1b[?25h"
+	ClearScreen    = "\x1b[H\x1b[2J"
+	ClearLine      = "\x1b[K"
+	ClearToEnd     = "\x1b[J"
+	Home           = "\x1b[H"
+	Reverse        = "\x1b[7m"
+	Reset          = "\x1b[0m"
+)
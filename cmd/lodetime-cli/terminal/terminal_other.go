@@ -0,0 +1,33 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package terminal
+
+import "time"
+
+// State is the terminal mode to restore after MakeRaw.
+type State struct{}
+
+// IsTerminal reports whether fd is a terminal.
+func IsTerminal(fd int) bool {
+	return false
+}
+
+// MakeRaw is not supported on this platform.
+func MakeRaw(fd int) (*State, error) {
+	return nil, ErrUnsupported
+}
+
+// Restore is not supported on this platform.
+func Restore(fd int, state *State) error {
+	return ErrUnsupported
+}
+
+// Size is not supported on this platform.
+func Size(fd int) (int, int, error) {
+	return 0, 0, ErrUnsupported
+}
+
+// Readable is not supported on this platform.
+func Readable(fd int, timeout time.Duration) (bool, error) {
+	return false, ErrUnsupported
+}
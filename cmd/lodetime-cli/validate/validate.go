@@ -0,0 +1,530 @@
+// Package validate checks a .lodetime/ directory without the runtime. It
+// covers what scripts/phase-0/validate-lodetime/validate_lodetime.exs checks
+// (required keys, references, duplicate IDs) plus schema versions, statuses,
+// dependency cycles, build_order coverage, zone overlaps and whether component
+// statuses and tests match the files on disk.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lodetime/lodetime-cli/graph"
+	"github.com/lodetime/lodetime-cli/model"
+)
+
+// SchemaVersion is the .lodetime/ schema version this validator understands.
+const SchemaVersion = 1
+
+// Severity ranks a finding: info is FYI, warn is visible but does not fail
+// checks, error fails lode check and block also stops "tell" updates.
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+	SeverityBlock Severity = "block"
+)
+
+// Severities lists the severities from least to most severe.
+var Severities = []Severity{SeverityInfo, SeverityWarn, SeverityError, SeverityBlock}
+
+// ParseSeverity parses a severity name; "warning" is accepted for warn.
+func ParseSeverity(name string) (Severity, error) {
+	if name == "warning" {
+		return SeverityWarn, nil
+	}
+	for _, severity := range Severities {
+		if string(severity) == name {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("unknown severity %q (expected one of: info, warn, error, block)", name)
+}
+
+// Rank orders severities; unknown severities rank below info.
+func (s Severity) Rank() int {
+	for i, severity := range Severities {
+		if severity == s {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as severe as threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.Rank() >= threshold.Rank()
+}
+
+// Rule IDs reported in findings.
+const (
+	RuleParse             = "parse"
+	RuleUnknownKey        = "unknown-key"
+	RuleRequiredField     = "required-field"
+	RuleSchemaVersion     = "schema-version"
+	RuleInvalidStatus     = "invalid-status"
+	RuleDuplicateID       = "duplicate-id"
+	RuleUnknownDependency = "unknown-dependency"
+	RuleUnknownContract   = "unknown-contract"
+	RuleBuildOrder        = "build-order"
+	RuleZoneOverlap       = "zone-overlap"
+	RuleCircularDeps      = "no-circular-deps"
+	RuleInvalidSeverity   = "invalid-severity"
+	RuleStatusLocation    = "status-location"
+	RuleMissingTest       = "missing-test"
+	// RuleYAMLDrift is reported by lode check --staged when code under a
+	// component is staged without its component YAML.
+	RuleYAMLDrift = "yaml-drift"
+	// RuleTestFailure is reported by lode check --staged when a test declared
+	// by an affected component fails or has no runner.
+	RuleTestFailure = "test-failure"
+)
+
+// Rules lists every rule ID the validator reports.
+var Rules = []string{
+	RuleParse, RuleUnknownKey, RuleRequiredField, RuleSchemaVersion, RuleInvalidStatus,
+	RuleDuplicateID, RuleUnknownDependency, RuleUnknownContract, RuleBuildOrder,
+	RuleZoneOverlap, RuleCircularDeps, RuleInvalidSeverity, RuleStatusLocation,
+	RuleMissingTest, RuleYAMLDrift, RuleTestFailure,
+}
+
+var (
+	configRequired    = []string{"project", "schema_version", "current_phase", "zones"}
+	componentRequired = []string{"id", "schema_version", "name", "status", "location", "depends_on"}
+	contractRequired  = []string{"id", "schema_version", "name", "description"}
+)
+
+// Finding is one validation result.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Location formats the finding's file and line for display.
+func (f Finding) Location() string {
+	switch {
+	case f.File == "":
+		return ""
+	case f.Line > 0:
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Report is the result of validating a project.
+type Report struct {
+	Findings []Finding `json:"findings"`
+	// Project is nil when .lodetime/ could not be loaded.
+	Project *model.Project `json:"-"`
+}
+
+// Count returns the number of findings with severity.
+func (r *Report) Count(severity Severity) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// OK reports whether there are no error or block findings.
+func (r *Report) OK() bool {
+	return !r.Worst().AtLeast(SeverityError)
+}
+
+// Worst returns the most severe finding's severity, or "" without findings.
+func (r *Report) Worst() Severity {
+	var worst Severity
+	for _, finding := range r.Findings {
+		if finding.Severity.Rank() > worst.Rank() {
+			worst = finding.Severity
+		}
+	}
+	return worst
+}
+
+func (r *Report) add(rule string, severity Severity, source model.Source, key, format string, args ...any) {
+	r.Findings = append(r.Findings, Finding{
+		Rule:     rule,
+		Severity: severity,
+		File:     source.File,
+		Line:     source.Line(key),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Dir loads and validates lodeDir. Files that cannot be parsed are reported
+// as findings rather than returned as an error.
+func Dir(lodeDir string) *Report {
+	return DirIn(lodeDir, filepath.Dir(lodeDir))
+}
+
+// DirIn is Dir for a copy of .lodetime/ kept outside its project, such as the
+// staged files checked out by lode check --staged: the tree rules look for
+// code and tests under root instead of the copy's parent.
+func DirIn(lodeDir, root string) *Report {
+	project, err := model.Load(lodeDir)
+	if err != nil {
+		report := &Report{}
+		var loadErr *model.LoadError
+		if !errors.As(err, &loadErr) {
+			report.Findings = append(report.Findings, Finding{Rule: RuleParse, Severity: SeverityError, Message: err.Error()})
+			return report
+		}
+		for _, problem := range loadErr.Problems {
+			report.Findings = append(report.Findings, Finding{
+				Rule:     RuleParse,
+				Severity: SeverityError,
+				File:     problem.File,
+				Line:     problem.Line,
+				Message:  problem.Message,
+			})
+		}
+		return report
+	}
+
+	project.Root = root
+	return Project(project)
+}
+
+// Project validates a loaded project.
+func Project(project *model.Project) *Report {
+	report := &Report{Project: project}
+
+	for _, warning := range project.Warnings {
+		report.Findings = append(report.Findings, Finding{
+			Rule:     RuleUnknownKey,
+			Severity: SeverityWarn,
+			File:     warning.File,
+			Line:     warning.Line,
+			Message:  warning.Message,
+		})
+	}
+
+	checkConfig(report, &project.Config)
+	checkComponents(report, project)
+	checkContracts(report, project)
+	checkCycles(report, project)
+	checkBuildOrder(report, project)
+	checkZones(report, &project.Config)
+	checkTree(report, project)
+	applyRuleSeverities(report, &project.Config)
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	return report
+}
+
+func checkRequired(report *Report, source model.Source, required []string) {
+	for _, key := range required {
+		if !source.Has(key) {
+			report.add(RuleRequiredField, SeverityError, source, key, "missing required key `%s`", key)
+		}
+	}
+}
+
+func checkSchemaVersion(report *Report, source model.Source, version int) {
+	if source.Has("schema_version") && version != SchemaVersion {
+		report.add(RuleSchemaVersion, SeverityError, source, "schema_version",
+			"unsupported schema_version %d (supported: %d)", version, SchemaVersion)
+	}
+}
+
+func checkConfig(report *Report, config *model.Config) {
+	checkRequired(report, config.Source, configRequired)
+	checkSchemaVersion(report, config.Source, config.SchemaVersion)
+}
+
+// applyRuleSeverities lets config.yaml rules override the default severity
+// of a rule's findings, e.g. {id: no-circular-deps, severity: block}.
+func applyRuleSeverities(report *Report, config *model.Config) {
+	overrides := map[string]Severity{}
+	for _, rule := range config.Rules {
+		severity, err := ParseSeverity(rule.Severity)
+		if err != nil {
+			report.add(RuleInvalidSeverity, SeverityError, config.Source, "rules", "rule `%s`: %v", rule.ID, err)
+			continue
+		}
+		overrides[rule.ID] = severity
+	}
+
+	for i, finding := range report.Findings {
+		if severity, ok := overrides[finding.Rule]; ok {
+			report.Findings[i].Severity = severity
+		}
+	}
+}
+
+// RuleSeverity returns the severity config.yaml assigns to rule, or
+// fallback, for findings produced outside Project.
+func RuleSeverity(config *model.Config, rule string, fallback Severity) Severity {
+	for _, configured := range config.Rules {
+		if configured.ID != rule {
+			continue
+		}
+		if severity, err := ParseSeverity(configured.Severity); err == nil {
+			return severity
+		}
+	}
+	return fallback
+}
+
+func checkComponents(report *Report, project *model.Project) {
+	components := map[string]bool{}
+	contracts := map[string]bool{}
+	for _, component := range project.Components {
+		components[component.ID] = true
+	}
+	for _, contract := range project.Contracts {
+		contracts[contract.ID] = true
+	}
+
+	for _, component := range project.Components {
+		checkRequired(report, component.Source, componentRequired)
+		checkSchemaVersion(report, component.Source, component.SchemaVersion)
+
+		if component.Status != "" && !isStatus(component.Status) {
+			report.add(RuleInvalidStatus, SeverityError, component.Source, "status",
+				"invalid status `%s` (expected one of: %s)", component.Status, strings.Join(model.Statuses, ", "))
+		}
+		for _, dep := range component.DependsOn {
+			if !components[dep] {
+				report.add(RuleUnknownDependency, SeverityError, component.Source, "depends_on",
+					"unknown depends_on component `%s`", dep)
+			}
+		}
+		for _, contract := range component.ImplementsContracts {
+			if !contracts[contract] {
+				report.add(RuleUnknownContract, SeverityError, component.Source, "implements_contracts",
+					"unknown contract `%s` in implements_contracts", contract)
+			}
+		}
+	}
+
+	checkDuplicates(report, "component", project.Components, func(c model.Component) (string, model.Source) {
+		return c.ID, c.Source
+	})
+}
+
+func checkContracts(report *Report, project *model.Project) {
+	for _, contract := range project.Contracts {
+		checkRequired(report, contract.Source, contractRequired)
+		checkSchemaVersion(report, contract.Source, contract.SchemaVersion)
+	}
+
+	checkDuplicates(report, "contract", project.Contracts, func(c model.Contract) (string, model.Source) {
+		return c.ID, c.Source
+	})
+}
+
+func checkDuplicates[T any](report *Report, label string, records []T, key func(T) (string, model.Source)) {
+	sources := map[string][]model.Source{}
+	ids := []string{}
+	for _, record := range records {
+		id, source := key(record)
+		if _, seen := sources[id]; !seen {
+			ids = append(ids, id)
+		}
+		sources[id] = append(sources[id], source)
+	}
+
+	for _, id := range ids {
+		if len(sources[id]) < 2 {
+			continue
+		}
+		files := []string{}
+		for _, source := range sources[id] {
+			files = append(files, source.File)
+		}
+		for _, source := range sources[id][1:] {
+			report.add(RuleDuplicateID, SeverityError, source, "id",
+				"duplicate %s id `%s` in %s", label, id, strings.Join(files, ", "))
+		}
+	}
+}
+
+// checkCycles reports each dependency cycle once, at the depends_on of the
+// first component on the path.
+func checkCycles(report *Report, project *model.Project) {
+	for _, cycle := range graph.New(project.Components).Cycles() {
+		component, _ := project.Component(cycle[0])
+		report.add(RuleCircularDeps, SeverityError, component.Source, "depends_on",
+			"circular dependency: %s", cycle)
+	}
+}
+
+// checkBuildOrder checks build_order against the components and their
+// depends_on: every component listed once, after its dependencies.
+func checkBuildOrder(report *Report, project *model.Project) {
+	config := &project.Config
+	if !config.Has("build_order") {
+		return
+	}
+
+	position := map[string]int{}
+	for i, id := range config.BuildOrder {
+		if _, listed := position[id]; listed {
+			report.add(RuleBuildOrder, SeverityError, config.Source, "build_order",
+				"build_order lists `%s` more than once", id)
+			continue
+		}
+		position[id] = i
+		if _, ok := project.Component(id); !ok {
+			report.add(RuleBuildOrder, SeverityError, config.Source, "build_order",
+				"build_order lists unknown component `%s`", id)
+		}
+	}
+
+	for i, id := range config.BuildOrder {
+		component, ok := project.Component(id)
+		if !ok || position[id] != i {
+			continue
+		}
+		for _, dep := range component.DependsOn {
+			if at, ok := position[dep]; ok && at > i {
+				report.add(RuleBuildOrder, SeverityError, config.Source, "build_order",
+					"build_order lists `%s` before its dependency `%s`", id, dep)
+			}
+		}
+	}
+
+	for _, component := range project.Components {
+		if _, listed := position[component.ID]; !listed {
+			report.add(RuleBuildOrder, SeverityWarn, config.Source, "build_order",
+				"component `%s` is missing from build_order", component.ID)
+		}
+	}
+}
+
+// checkZones flags paths claimed by more than one zone. Identical paths are
+// errors; nested paths are warnings since the more specific zone wins.
+func checkZones(report *Report, config *model.Config) {
+	type zonePath struct {
+		zone string
+		path string
+	}
+
+	names := make([]string, 0, len(config.Zones))
+	for name := range config.Zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	paths := []zonePath{}
+	for _, name := range names {
+		for _, path := range config.Zones[name].Paths {
+			paths = append(paths, zonePath{zone: name, path: normalizePath(path)})
+		}
+	}
+
+	for i := 0; i < len(paths); i++ {
+		for j := i + 1; j < len(paths); j++ {
+			a, b := paths[i], paths[j]
+			if a.zone == b.zone {
+				continue
+			}
+			switch {
+			case a.path == b.path:
+				report.add(RuleZoneOverlap, SeverityError, config.Source, "zones",
+					"zones `%s` and `%s` both claim `%s/`", a.zone, b.zone, a.path)
+			case within(a.path, b.path) || within(b.path, a.path):
+				report.add(RuleZoneOverlap, SeverityWarn, config.Source, "zones",
+					"zone `%s` path `%s/` overlaps zone `%s` path `%s/`", a.zone, a.path, b.zone, b.path)
+			}
+		}
+	}
+}
+
+func normalizePath(path string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(path, "./"), "/")
+}
+
+// within reports whether path is inside dir (both normalized).
+func within(path, dir string) bool {
+	return dir == "" || strings.HasPrefix(path, dir+"/")
+}
+
+// checkTree compares component statuses with the tree: implemented
+// components need code at their location, planned ones should have none yet,
+// and declared tests must exist. It is skipped without a project root.
+func checkTree(report *Report, project *model.Project) {
+	if project.Root == "" {
+		return
+	}
+
+	for _, component := range project.Components {
+		location := normalizePath(component.Location)
+		switch component.Status {
+		case model.StatusImplemented:
+			if component.Location == "" {
+				report.add(RuleStatusLocation, SeverityWarn, component.Source, "location",
+					"component `%s` is implemented but has no location", component.ID)
+			} else if !hasCode(filepath.Join(project.Root, filepath.FromSlash(location))) {
+				report.add(RuleStatusLocation, SeverityWarn, component.Source, "location",
+					"component `%s` is implemented but `%s` is missing or empty", component.ID, component.Location)
+			}
+		case model.StatusPlanned:
+			if component.Location != "" && hasCode(filepath.Join(project.Root, filepath.FromSlash(location))) {
+				report.add(RuleStatusLocation, SeverityWarn, component.Source, "location",
+					"component `%s` is planned but `%s` already contains code", component.ID, component.Location)
+			}
+		}
+
+		for _, test := range component.Tests {
+			matches, err := filepath.Glob(filepath.Join(project.Root, filepath.FromSlash(test)))
+			if err != nil || len(matches) == 0 {
+				report.add(RuleMissingTest, SeverityWarn, component.Source, "tests",
+					"component `%s` lists test `%s`, which does not exist", component.ID, test)
+			}
+		}
+	}
+}
+
+// hasCode reports whether path is a file or a directory holding at least one
+// file. Hidden files and directories, such as .gitkeep, do not count.
+func hasCode(path string) bool {
+	found := false
+	_ = filepath.WalkDir(path, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fs.SkipDir
+		}
+		if current != path && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+func isStatus(status string) bool {
+	for _, valid := range model.Statuses {
+		if status == valid {
+			return true
+		}
+	}
+	return false
+}
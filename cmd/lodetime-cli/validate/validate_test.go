@@ -0,0 +1,236 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	lodeDir := filepath.Join(t.TempDir(), ".lodetime")
+	for _, dir := range []string{"components", "contracts"} {
+		if err := os.MkdirAll(filepath.Join(lodeDir, dir), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	writeFiles(t, lodeDir, files)
+	return lodeDir
+}
+
+// writeFiles writes files, keyed by slash-separated path, under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+const validConfig = `project: demo
+schema_version: 1
+current_phase: 1
+zones:
+  core:
+    paths: [lib/]
+build_order: [a, b]
+`
+
+func component(id, extra string) string {
+	return "id: " + id + "\nschema_version: 1\nname: " + id + "\nstatus: planned\nlocation: lib/" + id + "/\n" + extra
+}
+
+// findings renders findings as "severity rule file:line" for compact assertions.
+func findings(report *Report) []string {
+	out := []string{}
+	for _, finding := range report.Findings {
+		out = append(out, string(finding.Severity)+" "+finding.Rule+" "+finding.Location())
+	}
+	return out
+}
+
+func assertFindings(t *testing.T, report *Report, want ...string) {
+	t.Helper()
+
+	got := strings.Join(findings(report), "\n")
+	if got != strings.Join(want, "\n") {
+		t.Fatalf("unexpected findings:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestValidProjectPasses(t *testing.T) {
+	report := Dir(writeProject(t, map[string]string{
+		"config.yaml":        validConfig,
+		"components/a.yaml":  component("a", "depends_on: []\nimplements_contracts: [api]\n"),
+		"components/b.yaml":  component("b", "depends_on: [a]\n"),
+		"contracts/api.yaml": "id: api\nschema_version: 1\nname: API\ndescription: d\n",
+	}))
+
+	if !report.OK() || len(report.Findings) != 0 {
+		t.Fatalf("expected clean report, got %v", findings(report))
+	}
+}
+
+func TestReferencesRequiredFieldsAndStatus(t *testing.T) {
+	report := Dir(writeProject(t, map[string]string{
+		"config.yaml":       validConfig,
+		"components/a.yaml": component("a", "depends_on: [ghost]\nimplements_contracts: [nope]\n"),
+		"components/b.yaml": "id: b\nschema_version: 2\nname: b\nstatus: done\nlocation: lib/b/\n",
+	}))
+
+	assertFindings(t, report,
+		"error unknown-dependency components/a.yaml:6",
+		"error unknown-contract components/a.yaml:7",
+		"error required-field components/b.yaml:1",
+		"error schema-version components/b.yaml:2",
+		"error invalid-status components/b.yaml:4",
+	)
+}
+
+func TestDuplicateIDsBuildOrderAndZones(t *testing.T) {
+	report := Dir(writeProject(t, map[string]string{
+		"config.yaml": `project: demo
+schema_version: 1
+current_phase: 1
+zones:
+  core:
+    paths: [lib/]
+  legacy:
+    paths: [lib/legacy/]
+  app:
+    paths: [lib]
+build_order: [a, ghost, a]
+`,
+		"components/a.yaml":      component("a", "depends_on: []\n"),
+		"components/a-copy.yaml": component("a", "depends_on: []\n"),
+		"components/b.yaml":      component("b", "depends_on: []\n"),
+	}))
+
+	assertFindings(t, report,
+		"error duplicate-id components/a.yaml:1",
+		"error zone-overlap config.yaml:4",
+		"warn zone-overlap config.yaml:4",
+		"warn zone-overlap config.yaml:4",
+		"error build-order config.yaml:11",
+		"error build-order config.yaml:11",
+		"warn build-order config.yaml:11",
+	)
+}
+
+func TestBuildOrderBeforeDependency(t *testing.T) {
+	report := Dir(writeProject(t, map[string]string{
+		"config.yaml":       strings.Replace(validConfig, "[a, b]", "[b, a]", 1),
+		"components/a.yaml": component("a", "depends_on: []\n"),
+		"components/b.yaml": component("b", "depends_on: [a]\n"),
+	}))
+
+	assertFindings(t, report, "error build-order config.yaml:7")
+	if report.Findings[0].Message != "build_order lists `b` before its dependency `a`" {
+		t.Fatalf("unexpected message: %s", report.Findings[0].Message)
+	}
+}
+
+func TestCircularDependencies(t *testing.T) {
+	report := Dir(writeProject(t, map[string]string{
+		"config.yaml":       validConfig,
+		"components/a.yaml": component("a", "depends_on: [b]\n"),
+		"components/b.yaml": component("b", "depends_on: [a]\n"),
+	}))
+
+	assertFindings(t, report,
+		"error no-circular-deps components/a.yaml:6",
+		"error build-order config.yaml:7",
+	)
+	if report.Findings[0].Message != "circular dependency: a → b → a" {
+		t.Fatalf("unexpected message: %s", report.Findings[0].Message)
+	}
+}
+
+func TestRuleSeverityOverrides(t *testing.T) {
+	report := Dir(writeProject(t, map[string]string{
+		"config.yaml":       validConfig + "rules:\n  - {id: no-circular-deps, severity: block}\n  - {id: build-order, severity: warning}\n  - {id: zone-overlap, severity: fatal}\n",
+		"components/a.yaml": component("a", "depends_on: [b]\n"),
+		"components/b.yaml": component("b", "depends_on: [a]\n"),
+	}))
+
+	assertFindings(t, report,
+		"block no-circular-deps components/a.yaml:6",
+		"warn build-order config.yaml:7",
+		"error invalid-severity config.yaml:8",
+	)
+	if report.Worst() != SeverityBlock || report.OK() {
+		t.Fatalf("expected block to fail the report, worst %q", report.Worst())
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for name, want := range map[string]Severity{"info": SeverityInfo, "warning": SeverityWarn, "block": SeverityBlock} {
+		if got, err := ParseSeverity(name); err != nil || got != want {
+			t.Fatalf("ParseSeverity(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Fatalf("expected unknown severity to fail")
+	}
+	if !SeverityBlock.AtLeast(SeverityError) || SeverityWarn.AtLeast(SeverityError) {
+		t.Fatalf("unexpected severity ranking")
+	}
+}
+
+func TestParseErrorsBecomeFindings(t *testing.T) {
+	report := Dir(writeProject(t, map[string]string{
+		"config.yaml":       validConfig,
+		"components/a.yaml": "id: a\ndepends_on: [b\n",
+	}))
+
+	if report.OK() || report.Project != nil {
+		t.Fatalf("expected failed report without project")
+	}
+	if report.Findings[0].Rule != RuleParse || report.Findings[0].Line == 0 {
+		t.Fatalf("expected located parse finding, got %+v", report.Findings[0])
+	}
+}
+
+func TestStatusAndTestsMatchTree(t *testing.T) {
+	lodeDir := writeProject(t, map[string]string{
+		"config.yaml":       "project: demo\nschema_version: 1\ncurrent_phase: 1\nzones:\n  core:\n    paths: [lib/]\n",
+		"components/a.yaml": "id: a\nschema_version: 1\nname: a\nstatus: implemented\nlocation: lib/a/\ndepends_on: []\ntests: [test/a_test.exs]\n",
+		"components/b.yaml": "id: b\nschema_version: 1\nname: b\nstatus: implemented\nlocation: lib/b/\ndepends_on: []\n",
+		"components/c.yaml": "id: c\nschema_version: 1\nname: c\nstatus: planned\nlocation: lib/c/\ndepends_on: []\n",
+		"components/d.yaml": "id: d\nschema_version: 1\nname: d\nstatus: planned\nlocation: lib/d/\ndepends_on: []\ntests: [test/d_test.exs]\n",
+		"components/e.yaml": "id: e\nschema_version: 1\nname: e\nstatus: implemented\nlocation: \"\"\ndepends_on: []\n",
+	})
+	root := filepath.Dir(lodeDir)
+	writeFiles(t, root, map[string]string{
+		"lib/a/a.ex":        "defmodule A do\nend\n",
+		"lib/b/.gitkeep":    "",
+		"lib/c/nested/c.ex": "defmodule C do\nend\n",
+		"lib/d/.gitkeep":    "",
+		"test/a_test.exs":   "",
+	})
+
+	report := Dir(lodeDir)
+	assertFindings(t, report,
+		"warn status-location components/b.yaml:5",
+		"warn status-location components/c.yaml:5",
+		"warn missing-test components/d.yaml:7",
+		"warn status-location components/e.yaml:5",
+	)
+	if !strings.Contains(report.Findings[0].Message, "implemented but `lib/b/` is missing or empty") {
+		t.Fatalf("unexpected message: %s", report.Findings[0].Message)
+	}
+
+	writeFiles(t, root, map[string]string{"test/d_test.exs": ""})
+	for _, finding := range Dir(lodeDir).Findings {
+		if finding.Rule == RuleMissingTest {
+			t.Fatalf("unexpected missing-test finding: %+v", finding)
+		}
+	}
+}
@@ -20,10 +20,10 @@ defmodule LodeTime.Application do
       # Phase 1 components (uncomment as implemented):
       # LodeTime.Config.Server,
       LodeTime.Graph.Server,
-      LodeTime.Interface.CliSocket,
+      cli_interface(),
       
       # Phase 2 components:
-      # LodeTime.Watcher.Supervisor,
+      file_watcher(),
       # LodeTime.State.Server,
       # LodeTime.Runner.Supervisor,
       
@@ -43,4 +43,22 @@ defmodule LodeTime.Application do
     opts = [strategy: :one_for_one, name: LodeTime.Supervisor]
     Supervisor.start_link(children, opts)
   end
+
+  # `lode` spawns the runtime with LODE_RUNTIME_ENDPOINT=stdio for one-shot
+  # commands; otherwise the CLI talks to the socket listener.
+  defp cli_interface do
+    case System.get_env("LODE_RUNTIME_ENDPOINT") do
+      "stdio" -> LodeTime.Interface.CliStdio
+      _ -> LodeTime.Interface.CliSocket
+    end
+  end
+
+  # A one-shot stdio runtime exits after its command, so only a long-lived
+  # runtime watches files (nil is dropped by the filter above).
+  defp file_watcher do
+    case System.get_env("LODE_RUNTIME_ENDPOINT") do
+      "stdio" -> nil
+      _ -> LodeTime.Watcher.Server
+    end
+  end
 end
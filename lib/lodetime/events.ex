@@ -0,0 +1,45 @@
+defmodule LodeTime.Events do
+  @moduledoc """
+  Runtime events streamed to CLI connections that sent `subscribe`.
+
+  Events are broadcast on `LodeTime.PubSub` as `{:lodetime_event, event}`
+  where `event` is `%{type: type, at: iso8601, data: map}`.
+  """
+
+  @topic "runtime_events"
+  # checkpoint_started and checkpoint_finished join once the runtime handles
+  # the checkpoint command; until then nothing could emit them.
+  @types ~w(file_changed component_status_changed error_logged)
+
+  def types, do: @types
+
+  def subscribe do
+    Phoenix.PubSub.subscribe(LodeTime.PubSub, @topic)
+  end
+
+  # Broadcasting is a no-op when PubSub is not running (e.g. a server started
+  # on its own in tests).
+  def broadcast(type, data \\ %{}) when type in @types do
+    if Process.whereis(LodeTime.PubSub) do
+      event = %{type: type, at: DateTime.to_iso8601(DateTime.utc_now()), data: data}
+      Phoenix.PubSub.broadcast(LodeTime.PubSub, @topic, {:lodetime_event, event})
+    else
+      :ok
+    end
+  end
+
+  def validate_types(types) when is_list(types) do
+    case Enum.reject(types, &(&1 in @types)) do
+      [] -> :ok
+      unknown -> {:error, unknown}
+    end
+  end
+
+  def validate_types(_types), do: {:error, :invalid}
+
+  def wanted?(_event, []), do: true
+  def wanted?(%{type: type}, types), do: type in types
+
+  # Wire format: {"event": type, "at": ..., "data": {...}}
+  def to_wire(%{type: type, at: at, data: data}), do: %{event: type, at: at, data: data}
+end
@@ -4,6 +4,7 @@ defmodule LodeTime.Graph.Server do
   use GenServer
 
   alias LodeTime.Config.Loader
+  alias LodeTime.Events
   alias LodeTime.Graph.LogSink
 
   @log_path "logs/graph-server/runtime.log"
@@ -132,6 +133,7 @@ defmodule LodeTime.Graph.Server do
             degraded: false
         }
 
+        broadcast_status_changes(graph_for_summary(state), graph)
         {:reply, :ok, new_state}
 
       {:error, errors} ->
@@ -142,6 +144,12 @@ defmodule LodeTime.Graph.Server do
             degraded: true
         }
 
+        Events.broadcast("error_logged", %{
+          process: "graph-server",
+          count: length(errors),
+          message: List.last(errors).message
+        })
+
         {:reply, {:error, errors}, new_state}
     end
   end
@@ -156,6 +164,17 @@ defmodule LodeTime.Graph.Server do
     end
   end
 
+  defp broadcast_status_changes(old_graph, new_graph) do
+    Enum.each(new_graph.components, fn {id, component} ->
+      from = get_in(old_graph.components, [id, "status"])
+      to = component["status"]
+
+      if from != to do
+        Events.broadcast("component_status_changed", %{component: id, from: from, to: to})
+      end
+    end)
+  end
+
   defp empty_graph do
     %{components: %{}, contracts: %{}}
   end
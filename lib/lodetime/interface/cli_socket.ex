@@ -18,8 +18,7 @@ defmodule LodeTime.Interface.CliSocket do
   end
 
   def start_link(opts \\ []) do
-    port = Keyword.get(opts, :port, @default_port)
-    ip = Keyword.get(opts, :ip, @default_ip)
+    {ip, port} = listen_address(opts)
     graph_server = Keyword.get(opts, :graph_server, LodeTime.Graph.Server)
     log_path = Keyword.get(opts, :log_path, @log_path)
 
@@ -33,6 +32,22 @@ defmodule LodeTime.Interface.CliSocket do
     )
   end
 
+  # A `unix://` endpoint (from opts or LODE_RUNTIME_ENDPOINT) switches the
+  # listener to a Unix domain socket; anything else keeps TCP loopback.
+  defp listen_address(opts) do
+    endpoint = Keyword.get(opts, :endpoint, System.get_env("LODE_RUNTIME_ENDPOINT"))
+
+    case endpoint do
+      "unix://" <> path when path != "" ->
+        File.mkdir_p!(Path.dirname(path))
+        _ = File.rm(path)
+        {{:local, path}, 0}
+
+      _ ->
+        {Keyword.get(opts, :ip, @default_ip), Keyword.get(opts, :port, @default_port)}
+    end
+  end
+
   defp attach_logger(log_path) do
     File.mkdir_p!(Path.dirname(log_path))
     File.write!(log_path, "", [:append])
@@ -3,6 +3,7 @@ defmodule LodeTime.Interface.CliSocket.Handler do
 
   use ThousandIsland.Handler
 
+  alias LodeTime.Events
   alias ThousandIsland.Socket
 
   @impl ThousandIsland.Handler
@@ -10,13 +11,28 @@ defmodule LodeTime.Interface.CliSocket.Handler do
     buffer = (state[:buffer] || "") <> IO.iodata_to_binary(data)
     {lines, rest} = split_lines(buffer)
 
-    Enum.each(lines, fn line ->
-      handle_line(String.trim(line), socket, state)
-    end)
+    state =
+      Enum.reduce(lines, state, fn line, acc ->
+        handle_line(String.trim(line), socket, acc)
+      end)
 
-    {:continue, Map.put(state, :buffer, rest)}
+    continue(Map.put(state, :buffer, rest))
   end
 
+  # Subscribed connections stay open while idle instead of hitting the
+  # read timeout.
+  @impl GenServer
+  def handle_info({:lodetime_event, event}, {socket, state}) do
+    if Events.wanted?(event, state[:subscription]) do
+      send_response(socket, Events.to_wire(event))
+    end
+
+    {:noreply, {socket, state}, :infinity}
+  end
+
+  defp continue(%{subscription: _} = state), do: {:continue, state, :infinity}
+  defp continue(state), do: {:continue, state}
+
   defp split_lines(buffer) do
     parts = String.split(buffer, "\n")
     case parts do
@@ -25,23 +41,68 @@ defmodule LodeTime.Interface.CliSocket.Handler do
     end
   end
 
-  defp handle_line("", _socket, _state), do: :ok
+  defp handle_line("", _socket, state), do: state
 
   defp handle_line(line, socket, state) do
     case Jason.decode(line) do
-      {:ok, %{"cmd" => "status"} = req} ->
-        verbose = Map.get(req, "verbose", false)
-        data = status_payload(state[:graph_server], verbose)
-        send_response(socket, %{ok: true, data: data})
+      {:ok, %{"cmd" => "subscribe"} = req} ->
+        {reply, state} = subscribe(req, state)
+        send_response(socket, with_request_id(reply, req))
+        state
+
+      _ ->
+        send_response(socket, respond(line, state))
+        state
+    end
+  end
+
+  defp subscribe(req, state) do
+    types = Map.get(req, "types", [])
+
+    case Events.validate_types(types) do
+      :ok ->
+        unless Map.has_key?(state, :subscription), do: Events.subscribe()
+        {%{ok: true, data: %{subscribed: if(types == [], do: Events.types(), else: types)}},
+         Map.put(state, :subscription, types)}
+
+      {:error, _} ->
+        {error_payload("invalid_args", "types must be a list of: " <> Enum.join(Events.types(), ", ")),
+         state}
+    end
+  end
 
-      {:ok, %{"cmd" => _cmd}} ->
-        send_error(socket, "not_implemented", "command not implemented")
+  # Shared with LodeTime.Interface.CliStdio so both transports answer alike.
+  @doc false
+  def respond(line, state) do
+    case Jason.decode(line) do
+      {:ok, %{"cmd" => _cmd} = req} ->
+        req |> dispatch(state) |> with_request_id(req)
 
       {:error, _} ->
-        send_error(socket, "invalid_json", "invalid JSON")
+        error_payload("invalid_json", "invalid JSON")
     end
   end
 
+  defp dispatch(%{"cmd" => "status"} = req, state) do
+    verbose = Map.get(req, "verbose", false)
+    %{ok: true, data: status_payload(state[:graph_server], verbose)}
+  end
+
+  defp dispatch(%{"cmd" => "subscribe"}, _state) do
+    error_payload("not_supported", "subscribe requires a socket endpoint")
+  end
+
+  defp dispatch(_req, _state) do
+    error_payload("not_implemented", "command not implemented")
+  end
+
+  # The client matches responses to requests by request_id.
+  defp with_request_id(payload, %{"request_id" => id}) when not is_nil(id) do
+    Map.put(payload, :request_id, id)
+  end
+
+  defp with_request_id(payload, _req), do: payload
+
   defp status_payload(nil, _verbose), do: %{}
   defp status_payload(graph_server, _verbose) do
     if function_exported?(graph_server, :status_payload, 0) do
@@ -55,7 +116,7 @@ defmodule LodeTime.Interface.CliSocket.Handler do
     Socket.send(socket, Jason.encode!(payload) <> "\n")
   end
 
-  defp send_error(socket, code, message) do
-    send_response(socket, %{ok: false, error: %{code: code, message: message}})
+  defp error_payload(code, message) do
+    %{ok: false, error: %{code: code, message: message}}
   end
 end
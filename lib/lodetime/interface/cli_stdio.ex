@@ -0,0 +1,33 @@
+defmodule LodeTime.Interface.CliStdio do
+  @moduledoc false
+
+  # CLI protocol over stdin/stdout, used when `lode` spawns the runtime with
+  # LODE_RUNTIME_ENDPOINT=stdio. Closing stdin stops the runtime.
+
+  use Task, restart: :transient
+
+  alias LodeTime.Interface.CliSocket.Handler
+
+  def start_link(opts \\ []) do
+    graph_server = Keyword.get(opts, :graph_server, LodeTime.Graph.Server)
+    Task.start_link(__MODULE__, :run, [%{graph_server: graph_server}])
+  end
+
+  def run(state) do
+    case IO.read(:stdio, :line) do
+      :eof ->
+        System.stop(0)
+
+      {:error, _reason} ->
+        System.stop(1)
+
+      line ->
+        case String.trim(line) do
+          "" -> :ok
+          request -> IO.write(:stdio, Jason.encode!(Handler.respond(request, state)) <> "\n")
+        end
+
+        run(state)
+    end
+  end
+end
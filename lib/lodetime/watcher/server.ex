@@ -0,0 +1,110 @@
+defmodule LodeTime.Watcher.Server do
+  @moduledoc """
+  Watches the project for file changes.
+
+  Changed paths are collected for `triggers.file_system.debounce_ms`, then
+  broadcast as `file_changed` events; a change under `.lodetime/` also reloads
+  the graph server, which emits its own status and error events.
+  """
+
+  use GenServer
+
+  require Logger
+
+  alias LodeTime.Config.Loader
+  alias LodeTime.Events
+  alias LodeTime.Graph.Server
+
+  @default_debounce_ms 2000
+  # Build output, dependencies, logs and lode's own runtime files change on
+  # their own and would only add noise (or, for logs, feed back into events).
+  @ignored_dirs ~w(_build deps node_modules logs .git .elixir_ls)
+  @ignored_files ~w(.lodetime/session.json .lodetime/lode.sock)
+
+  def start_link(opts \\ []) do
+    name = Keyword.get(opts, :name, __MODULE__)
+
+    start_opts =
+      case name do
+        nil -> []
+        _ -> [name: name]
+      end
+
+    GenServer.start_link(__MODULE__, opts, start_opts)
+  end
+
+  @impl true
+  def init(opts) do
+    root_path = Keyword.get(opts, :root_path, File.cwd!())
+    trigger = file_system_trigger(root_path)
+
+    state = %{
+      root_path: Path.expand(root_path),
+      graph_server: Keyword.get(opts, :graph_server, Server),
+      debounce_ms: Keyword.get(opts, :debounce_ms, trigger["debounce_ms"] || @default_debounce_ms),
+      pending: MapSet.new(),
+      timer: nil
+    }
+
+    cond do
+      trigger["enabled"] == false -> :ignore
+      Keyword.get(opts, :watch, true) -> watch(state)
+      true -> {:ok, state}
+    end
+  end
+
+  # FileSystem stops with :ignore when no backend (e.g. inotify-tools) is
+  # installed; the runtime then keeps running without push events.
+  defp watch(state) do
+    case FileSystem.start_link(dirs: [state.root_path]) do
+      {:ok, watcher} ->
+        FileSystem.subscribe(watcher)
+        {:ok, state}
+
+      _ ->
+        Logger.warning("File watcher unavailable; file_changed events are disabled")
+        :ignore
+    end
+  end
+
+  @impl true
+  def handle_info({:file_event, _watcher, {path, _events}}, state) do
+    rel = Path.relative_to(Path.expand(path), state.root_path)
+
+    if ignored?(rel) do
+      {:noreply, state}
+    else
+      timer = state.timer || Process.send_after(self(), :flush, state.debounce_ms)
+      {:noreply, %{state | pending: MapSet.put(state.pending, rel), timer: timer}}
+    end
+  end
+
+  def handle_info({:file_event, _watcher, :stop}, state) do
+    {:stop, :normal, state}
+  end
+
+  def handle_info(:flush, state) do
+    paths = state.pending |> MapSet.to_list() |> Enum.sort()
+    Enum.each(paths, &Events.broadcast("file_changed", %{path: &1}))
+
+    if Enum.any?(paths, &String.starts_with?(&1, ".lodetime/")) do
+      Server.reload(state.graph_server)
+    end
+
+    {:noreply, %{state | pending: MapSet.new(), timer: nil}}
+  end
+
+  defp ignored?(rel) do
+    [top | _] = Path.split(rel)
+    Path.type(rel) == :absolute or top in @ignored_dirs or rel in @ignored_files
+  end
+
+  # A broken config still gets watched with the defaults, so fixing it
+  # triggers the reload that clears the error.
+  defp file_system_trigger(root_path) do
+    case Loader.load(root_path) do
+      {:ok, model} -> get_in(model.config, ["triggers", "file_system"]) || %{}
+      {:error, _errors} -> %{}
+    end
+  end
+end
@@ -49,4 +49,44 @@ defmodule LodeTime.Interface.CliSocketTest do
     :gen_tcp.close(socket)
     Supervisor.stop(pid)
   end
+
+  test "request_id is echoed in the response" do
+    {:ok, pid} = CliSocket.start_link(port: 0, graph_server: TestGraphServer)
+    {:ok, {_, port}} = ThousandIsland.listener_info(pid)
+
+    {:ok, socket} = :gen_tcp.connect({127, 0, 0, 1}, port, [:binary, active: false, packet: :line])
+    :ok = :gen_tcp.send(socket, Jason.encode!(%{cmd: "status", request_id: "7"}) <> "\n")
+    {:ok, resp} = :gen_tcp.recv(socket, 0, 1000)
+    assert Jason.decode!(resp)["request_id"] == "7"
+
+    :ok = :gen_tcp.send(socket, Jason.encode!(%{cmd: "nope", request_id: "8"}) <> "\n")
+    {:ok, resp} = :gen_tcp.recv(socket, 0, 1000)
+    data = Jason.decode!(resp)
+    assert data["request_id"] == "8"
+    assert data["error"]["code"] == "not_implemented"
+
+    :gen_tcp.close(socket)
+    Supervisor.stop(pid)
+  end
+
+  test "subscribe streams broadcast events" do
+    {:ok, pid} = CliSocket.start_link(port: 0, graph_server: TestGraphServer)
+    {:ok, {_, port}} = ThousandIsland.listener_info(pid)
+
+    {:ok, socket} = :gen_tcp.connect({127, 0, 0, 1}, port, [:binary, active: false, packet: :line])
+    :ok = :gen_tcp.send(socket, Jason.encode!(%{cmd: "subscribe", types: ["component_status_changed"]}) <> "\n")
+    {:ok, resp} = :gen_tcp.recv(socket, 0, 1000)
+    assert Jason.decode!(resp)["data"]["subscribed"] == ["component_status_changed"]
+
+    LodeTime.Events.broadcast("error_logged", %{process: "graph-server"})
+    LodeTime.Events.broadcast("component_status_changed", %{component: "cli", from: "planned", to: "implemented"})
+    {:ok, event} = :gen_tcp.recv(socket, 0, 1000)
+
+    data = Jason.decode!(event)
+    assert data["event"] == "component_status_changed"
+    assert data["data"]["component"] == "cli"
+
+    :gen_tcp.close(socket)
+    Supervisor.stop(pid)
+  end
 end
@@ -0,0 +1,52 @@
+defmodule LodeTime.Watcher.ServerTest do
+  use ExUnit.Case
+
+  alias LodeTime.Graph
+  alias LodeTime.Watcher.Server
+
+  test "broadcasts changed paths and reloads the graph on .lodetime/ changes" do
+    root = tmp_dir()
+    write_project(root, "planned")
+
+    log_path = Path.join(root, "logs/graph-server/runtime.log")
+    {:ok, graph} = Graph.Server.start_link(root_path: root, name: nil, log_path: log_path)
+    {:ok, watcher} =
+      Server.start_link(root_path: root, name: nil, graph_server: graph, watch: false, debounce_ms: 10)
+
+    LodeTime.Events.subscribe()
+    write_project(root, "implemented")
+
+    for path <- ["_build/dev/lib/x.beam", ".lodetime/components/config-loader.yaml", "lib/a.ex"] do
+      send(watcher, {:file_event, self(), {Path.join(root, path), [:modified]}})
+    end
+
+    assert_receive {:lodetime_event, %{type: "file_changed", data: %{path: ".lodetime/components/config-loader.yaml"}}}
+    assert_receive {:lodetime_event, %{type: "file_changed", data: %{path: "lib/a.ex"}}}
+    assert_receive {:lodetime_event,
+                    %{type: "component_status_changed", data: %{component: "config-loader", to: "implemented"}}}
+    refute_received {:lodetime_event, %{type: "file_changed", data: %{path: "_build/" <> _}}}
+
+    GenServer.stop(watcher)
+    GenServer.stop(graph)
+  end
+
+  defp tmp_dir do
+    dir = Path.join(System.tmp_dir!(), "lodetime-test-#{System.unique_integer([:positive])}")
+    File.mkdir_p!(dir)
+    dir
+  end
+
+  defp write_project(root, status) do
+    lodetime = Path.join(root, ".lodetime")
+    File.mkdir_p!(Path.join(lodetime, "components"))
+    File.mkdir_p!(Path.join(lodetime, "contracts"))
+    File.write!(
+      Path.join(lodetime, "config.yaml"),
+      "project: test\nschema_version: 1\ncurrent_phase: 1\nzones:\n  core:\n    paths: [lib/]\n    tracking: full\n"
+    )
+    File.write!(
+      Path.join([lodetime, "components", "config-loader.yaml"]),
+      "id: config-loader\nschema_version: 1\nname: Config Loader\nstatus: #{status}\nlocation: lib/lodetime/config/\ndepends_on: []\n"
+    )
+  end
+end